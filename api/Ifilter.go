@@ -2,7 +2,7 @@ package api
 
 // IFilter represents Transform Filter nodes
 type IFilter interface {
-	Visit(transStack ITransformStack, interpolation float64)
+	Visit(transStack ITransformStack, profiler IProfiler, interpolation float64)
 
 	InheritOnlyRotation()
 	InheritOnlyScale()
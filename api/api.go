@@ -5,6 +5,75 @@ package api
 // ------------------------------------------------------
 const (
 	RelativeShaderPath = "/engine/assets/shaders/"
+
+	// Paths within the Engine's file system
+	ShaderDir  = "engine/assets/shaders"
+	ConfigFile = "engine/configuration/config.json"
+	AssetDir   = "assets"
+)
+
+// ------------------------------------------------------
+// Backends
+// ------------------------------------------------------
+const (
+	// BackendOpenGL renders through a GLFW window and OpenGL. This is
+	// the default when config.json doesn't specify a Backend.
+	BackendOpenGL = "OpenGL"
+	// BackendHeadless uses a null display and no-op atlases. Nothing
+	// is rendered and no GL context is created.
+	BackendHeadless = "Headless"
+	// BackendSoftware rasterizes into an image in pure Go. No window
+	// or GL context is created.
+	BackendSoftware = "Software"
+)
+
+// ------------------------------------------------------
+// Asset types known to the World's asset manager
+// ------------------------------------------------------
+const (
+	AssetAtlas       = "Atlas"       // IAtlasX, added with AddAtlas
+	AssetSpriteSheet = "SpriteSheet" // ISpriteSheet, source is a manifest file
+	AssetRasterFont  = "RasterFont"  // IRasterFont, source is a file in /assets
+	AssetSound       = "Sound"       // IGeneratorValues, source is a sfxr json file
+)
+
+// ------------------------------------------------------
+// Run limit units for Engine.LoopFor
+// ------------------------------------------------------
+const (
+	LoopFrames  = "Frames"
+	LoopUpdates = "Updates"
+	LoopSeconds = "Seconds"
+)
+
+// ------------------------------------------------------
+// Scaling policies for mapping Window.VirtualRes onto the device.
+// ------------------------------------------------------
+const (
+	// ScaleNone ignores VirtualRes. One view unit is one device pixel.
+	// This is the default when config.json doesn't specify a policy.
+	ScaleNone = "None"
+	// ScaleStretch fills the device, distorting the aspect ratio.
+	ScaleStretch = "Stretch"
+	// ScaleLetterbox preserves the aspect ratio adding bars either
+	// top/bottom (letterbox) or left/right (pillarbox).
+	ScaleLetterbox = "Letterbox"
+	// ScalePixelPerfect is Letterbox restricted to integer scales.
+	ScalePixelPerfect = "PixelPerfect"
+	// ScaleExpand preserves the aspect ratio and fills the device by
+	// showing more of the view along one axis.
+	ScaleExpand = "Expand"
+)
+
+// ------------------------------------------------------
+// Profiler phases
+// ------------------------------------------------------
+const (
+	ProfilePhasePoll = iota
+	ProfilePhaseUpdate
+	ProfilePhaseVisit
+	ProfilePhaseSwap
+	ProfilePhaseCount
 )
 
 const (
@@ -0,0 +1,48 @@
+package api
+
+// IAssetLoader loads one type of asset. Decode may run on a background
+// goroutine so it must not make GL calls. Upload always runs on the
+// main thread and is where any GL resources are created.
+type IAssetLoader interface {
+	Decode(name, source string) (interface{}, error)
+	Upload(asset interface{}) error
+	Release(asset interface{})
+}
+
+// IAssetManager reference counts assets keyed by type and name.
+type IAssetManager interface {
+	RegisterLoader(assetType string, loader IAssetLoader)
+
+	// Load decodes and uploads an asset. If the asset is already
+	// loaded, or being preloaded, a reference is added instead.
+	Load(assetType, name, source string) (interface{}, error)
+
+	// Add adds an asset that was built in code, for example an atlas,
+	// with a single reference. An existing asset gains a reference.
+	Add(assetType, name string, asset interface{})
+
+	// Get returns an uploaded asset, or nil, without adding a reference.
+	Get(assetType, name string) interface{}
+
+	// Acquire adds a reference to an uploaded asset and returns it.
+	Acquire(assetType, name string) interface{}
+
+	// Release removes a reference. The loader releases the asset once
+	// no references remain.
+	Release(assetType, name string)
+
+	RefCount(assetType, name string) int
+
+	// Preload decodes an asset on a background goroutine. The asset is
+	// uploaded by a later Update and holds a single reference.
+	Preload(assetType, name, source string)
+
+	// Update uploads preloaded assets that have finished decoding. It
+	// is called by the Engine between frames on the main thread.
+	Update() []error
+
+	// Progress is how many preloaded assets are uploaded, or failed,
+	// out of how many have been requested.
+	Progress() (done, total int)
+	Loaded() bool
+}
@@ -6,4 +6,5 @@ type IDynamicPixelAtlasX interface {
 	SetVertex(x, y float32, index int)
 	SetData(vertices []float32, indices []uint32)
 	SetPixelActiveCount(count int)
+	SetPointSize(size float32)
 }
@@ -13,5 +13,9 @@ type IAtlasX interface {
 	UnUse()
 
 	SetColor(color []float32)
+	// SetTint multiplies the colors given to SetColor by an RGBA tint.
+	// Visit sets it to the opacity and tint the node being drawn
+	// inherits. nil removes it.
+	SetTint(tint []float32)
 	Render(shapeID int, model IMatrix4)
 }
@@ -0,0 +1,13 @@
+package api
+
+// IClickCounter counts successive presses of a mouse button for double
+// and triple clicks.
+type IClickCounter interface {
+	// Press returns the click count of a press at device position x, y
+	// at time "now" in nanoseconds. It is 1 unless the same button was
+	// pressed nearby, and recently, in which case the count continues.
+	Press(button uint8, x, y int32, now int64) uint8
+
+	// Clicks returns the count of button's most recent press, or 1.
+	Clicks(button uint8) uint8
+}
@@ -0,0 +1,18 @@
+package api
+
+// IClock is the engine's source of time. The default clock reads wall
+// time, but a manual clock can be injected for reproducible runs.
+type IClock interface {
+	// Now returns the current time in nanoseconds.
+	Now() int64
+}
+
+// IManualClock is a clock that only moves when told to.
+type IManualClock interface {
+	IClock
+
+	// Advance moves the clock forward by nanoseconds.
+	Advance(nanoseconds int64)
+	// Set sets the clock to an absolute time in nanoseconds.
+	Set(nanoseconds int64)
+}
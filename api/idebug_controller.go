@@ -0,0 +1,25 @@
+package api
+
+// IDebugController pauses, single steps and time scales the engine's
+// update loop. Rendering continues while paused.
+type IDebugController interface {
+	Paused() bool
+	SetPaused(paused bool)
+	TogglePause()
+
+	// RequestStep asks for a single update to be performed while paused.
+	RequestStep()
+	// TakeStep consumes a pending step request, if there is one.
+	TakeStep() bool
+
+	// TimeScale is applied to elapsed time. Less than 1.0 is slow
+	// motion and greater than 1.0 is fast-forward.
+	TimeScale() float64
+	SetTimeScale(scale float64)
+	// ScaleUp doubles the time scale
+	ScaleUp()
+	// ScaleDown halves the time scale
+	ScaleDown()
+	// ResetTimeScale sets the time scale back to 1.0
+	ResetTimeScale()
+}
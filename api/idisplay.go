@@ -0,0 +1,18 @@
+package api
+
+// IDisplay represents the surface the engine renders onto, for
+// example a GLFW window or a null (headless) display.
+type IDisplay interface {
+	Initialize(world IWorld) error
+
+	// Closed indicates the display wants the engine to stop.
+	Closed() bool
+	Poll()
+	Shutdown()
+
+	SetClearColor(r, g, b, a float32)
+
+	// Pre performs pre rendering tasks, for example, clearing.
+	Pre()
+	Swap()
+}
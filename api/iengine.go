@@ -5,9 +5,37 @@ type IEngine interface {
 	// Start launches the game loop
 	Begin() error
 
-	// Ends shuts down the engine
+	// Ends shuts down the engine. It doesn't exit the process, main
+	// should call os.Exit(engine.ExitCode()) after End if it cares.
 	End()
 
+	// Exit stops the game loop at the top of the next frame.
+	Exit(code int)
+	// ExitCode is the code given to Exit or set by a run limit.
+	ExitCode() int
+
 	// World provides access to the engine's world properties
 	World() IWorld
+
+	// SetClock replaces the clock the game loop reads time from.
+	SetClock(clock IClock)
+	Clock() IClock
+
+	// Step performs exactly "updates" fixed updates and, if "visit" is
+	// true, a single visit of the scene graph. It doesn't depend on wall
+	// time so it is reproducible. Step begins the scene graph on its
+	// first call. It returns false once there are no more scenes.
+	Step(updates int, visit bool) (bool, error)
+
+	// Ticks returns how many fixed updates have been performed.
+	Ticks() uint64
+
+	// DebugController is the same controller provided by the World.
+	DebugController() IDebugController
+
+	// Profiler is the same profiler provided by the World.
+	Profiler() IProfiler
+
+	// HotReloader is the same reloader provided by the World.
+	HotReloader() IHotReloader
 }
@@ -9,8 +9,77 @@ const (
 	IOTypeMouseButtonDown = 1025
 	// IOTypeMouseButtonUp is a mouse event
 	IOTypeMouseButtonUp = 1026
-	// IOTypeMouseWheel is a mouse event
+	// IOTypeMouseWheel is a mouse event. Scroll has the horizontal and
+	// vertical offsets.
 	IOTypeMouseWheel = 1027
+	// IOTypeText is a text input event. Rune is the character entered,
+	// after the keyboard layout and modifiers have been applied.
+	IOTypeText = 1028
+	// IOTypePointerEnter is synthesized when the pointer moves onto a
+	// node or one of its children.
+	IOTypePointerEnter = 1029
+	// IOTypePointerLeave is synthesized when the pointer moves off a
+	// node and all of its children.
+	IOTypePointerLeave = 1030
+	// IOTypePointerHover is synthesized for the topmost node under the
+	// pointer each time the pointer moves.
+	IOTypePointerHover = 1031
+	// IOTypeGamepadConnected is a gamepad event. Which is the gamepad.
+	IOTypeGamepadConnected = 2048
+	// IOTypeGamepadDisconnected is a gamepad event
+	IOTypeGamepadDisconnected = 2049
+	// IOTypeGamepadButtonDown is a gamepad event. Button is a GamepadButtonXXX.
+	IOTypeGamepadButtonDown = 2050
+	// IOTypeGamepadButtonUp is a gamepad event
+	IOTypeGamepadButtonUp = 2051
+	// IOTypeGamepadAxis is a gamepad event. Axis is a GamepadAxisXXX.
+	IOTypeGamepadAxis = 2052
+)
+
+// Keyboard and mouse button states as reported by GetState. They
+// match GLFW's actions.
+const (
+	KeyRelease = 0
+	KeyPress   = 1
+	KeyRepeat  = 2
+)
+
+// Keys the engine itself responds to, as reported by GetKeyCode. They
+// match GLFW's.
+const (
+	KeyEnter = 257
+	KeyTab   = 258
+	KeyRight = 262
+	KeyLeft  = 263
+	KeyDown  = 264
+	KeyUp    = 265
+)
+
+// Modifier bits as reported by GetKeyMotif. They match GLFW's.
+const (
+	ModShift   = 0x0001
+	ModControl = 0x0002
+	ModAlt     = 0x0004
+	ModSuper   = 0x0008
+)
+
+// Pointer dispatch phases as reported by GetPhase.
+const (
+	// PhaseNone is an event that isn't being dispatched by position.
+	PhaseNone = iota
+	// PhaseCapture is an event on its way down to the target.
+	PhaseCapture
+	// PhaseTarget is an event at the target.
+	PhaseTarget
+	// PhaseBubble is an event on its way back up from the target.
+	PhaseBubble
+)
+
+// Mouse buttons as reported by GetButton.
+const (
+	MouseButtonLeft   = 1
+	MouseButtonRight  = 2
+	MouseButtonMiddle = 3
 )
 
 // IEvent represents IO event system
@@ -20,6 +89,18 @@ type IEvent interface {
 	BeenHandled() bool
 	Handled(mark bool)
 
+	// Timestamp is when the event arrived, in nanoseconds, on the
+	// engine's clock.
+	SetTimestamp(int64)
+	GetTimestamp() int64
+
+	// Phase and Target are set while a pointer event is dispatched to
+	// the topmost node under the cursor.
+	SetPhase(int)
+	GetPhase() int
+	SetTarget(INode)
+	GetTarget() INode
+
 	SetMousePosition(x, y int32)
 	GetMousePosition() (x, y int32)
 	SetMouseRelMovement(x, y int32)
@@ -31,22 +112,36 @@ type IEvent interface {
 	SetType(uint32)
 	GetType() uint32
 
+	// Clicks counts successive presses of the same button, 2 for a
+	// double click and 3 for a triple click. A release has the same
+	// count as its press.
 	SetClicks(uint8)
 	GetClicks() uint8
 
+	SetScroll(x, y float64)
+	GetScroll() (x, y float64)
+
+	SetRune(rune)
+	GetRune() rune
+
 	SetButton(uint8)
 	GetButton() uint8
 
 	SetWhich(uint32)
 	GetWhich() uint32
 
+	SetAxis(axis uint8, value float32)
+	GetAxis() (axis uint8, value float32)
+
 	SetState(uint32)
 	GetState() uint32
 	SetRepeat(uint8)
 	GetRepeat() uint8
 
+	// KeyScan is the platform specific scancode.
 	SetKeyScan(uint32)
 	GetKeyScan() uint32
+	// KeyCode is the GLFW key, for example 65 for 'A'.
 	SetKeyCode(uint32)
 	GetKeyCode() uint32
 	SetKeyMotif(uint32)
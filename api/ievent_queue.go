@@ -0,0 +1,25 @@
+package api
+
+// IEventQueue holds a frame's events until the engine dispatches them,
+// which is once per frame before the frame's updates.
+type IEventQueue interface {
+	// Post appends a copy of event. Consecutive mouse motion is
+	// coalesced into the latest motion while coalescing is enabled.
+	Post(event IEvent)
+
+	// SetCoalesceMotion is enabled by default.
+	SetCoalesceMotion(coalesce bool)
+	CoalesceMotion() bool
+
+	// Len is the number of events waiting to be dispatched.
+	Len() int
+	// Peek returns the i'th waiting event. The event may be kept.
+	Peek(i int) IEvent
+	// Consume removes the i'th waiting event so it isn't dispatched.
+	Consume(i int)
+
+	// Dispatch routes the waiting events, oldest first, and empties
+	// the queue. Handlers only see the events after the one being
+	// routed when peeking.
+	Dispatch(route func(event IEvent))
+}
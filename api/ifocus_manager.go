@@ -0,0 +1,47 @@
+package api
+
+// Focus navigation directions
+const (
+	FocusNext = iota
+	FocusPrevious
+	FocusUp
+	FocusDown
+	FocusLeft
+	FocusRight
+)
+
+// IFocusable is implemented by nodes that can take keyboard focus.
+type IFocusable interface {
+	// Focusable can return false to skip the node, for example a
+	// disabled button.
+	Focusable() bool
+	// Focus is called when the node gains focus.
+	Focus()
+	// Blur is called when the node loses focus.
+	Blur()
+}
+
+// IFocusManager tracks the node that owns keyboard input. Only visible
+// focusable nodes within scenes that are on stage take part.
+type IFocusManager interface {
+	// Focused returns the focused node or nil.
+	Focused() INode
+	// SetFocus blurs the focused node and focuses node. A nil node
+	// just clears the focus.
+	SetFocus(node INode)
+	ClearFocus()
+
+	// Focusables returns the nodes that can take focus in tree order.
+	Focusables() []INode
+
+	// Navigate moves the focus in a FocusXXX direction. Next and
+	// Previous follow tree order and wrap. The others pick the
+	// nearest node in that direction. Returns false if the focus
+	// didn't move.
+	Navigate(direction int) bool
+
+	// Handle navigates with Tab, Shift+Tab, the arrow keys and the
+	// gamepad d-pad. The arrows and d-pad are ignored until something
+	// has focus.
+	Handle(IEvent) bool
+}
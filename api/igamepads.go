@@ -0,0 +1,81 @@
+package api
+
+// Gamepad buttons in the standard (SDL/GLFW) gamepad mapping.
+const (
+	GamepadButtonA = iota
+	GamepadButtonB
+	GamepadButtonX
+	GamepadButtonY
+	GamepadButtonLeftBumper
+	GamepadButtonRightBumper
+	GamepadButtonBack
+	GamepadButtonStart
+	GamepadButtonGuide
+	GamepadButtonLeftThumb
+	GamepadButtonRightThumb
+	GamepadButtonDpadUp
+	GamepadButtonDpadRight
+	GamepadButtonDpadDown
+	GamepadButtonDpadLeft
+	GamepadButtonCount
+)
+
+// Gamepad axes in the standard gamepad mapping. Sticks are within
+// [-1, 1] and triggers within [0, 1] once the dead-zone is applied.
+const (
+	GamepadAxisLeftX = iota
+	GamepadAxisLeftY
+	GamepadAxisRightX
+	GamepadAxisRightY
+	GamepadAxisLeftTrigger
+	GamepadAxisRightTrigger
+	GamepadAxisCount
+)
+
+// IGamepadSource provides the raw state of connected gamepads, for
+// example from GLFW or a mock.
+type IGamepadSource interface {
+	// Gamepads returns the ids of the connected gamepads.
+	Gamepads() []int
+	Name(id int) string
+
+	// State fills buttons and axes, sized GamepadButtonCount and
+	// GamepadAxisCount, in the standard mapping. Axes, including the
+	// triggers, are raw values within [-1, 1]. It returns false if the
+	// gamepad isn't connected.
+	State(id int, buttons []bool, axes []float32) bool
+}
+
+// IMockGamepadSource is a gamepad source driven by code, which allows
+// controller driven logic to be tested without hardware.
+type IMockGamepadSource interface {
+	IGamepadSource
+
+	Connect(id int, name string)
+	Disconnect(id int)
+
+	SetButton(id int, button int, down bool)
+	// SetAxis sets a raw value within [-1, 1]. A released trigger is -1.
+	SetAxis(id int, axis int, value float32)
+}
+
+// IGamepads polls a source and routes events for whatever changed
+// since the previous poll.
+type IGamepads interface {
+	SetSource(source IGamepadSource)
+	Source() IGamepadSource
+
+	// SetDeadZone sets the radial dead-zone of the sticks and the
+	// dead-zone of the triggers. Values within it read as zero and the
+	// remainder is rescaled to the full range.
+	SetDeadZone(zone float32)
+
+	// Poll routes connect, disconnect, button and axis events.
+	Poll(route func(event IEvent))
+
+	Connected() []int
+	Name(id int) string
+	Button(id int, button int) bool
+	// Axis is the value after the dead-zone has been applied.
+	Axis(id int, axis int) float32
+}
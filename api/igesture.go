@@ -0,0 +1,83 @@
+package api
+
+// Gesture kinds
+const (
+	GestureTap = iota
+	GestureLongPress
+	GestureDrag
+	GestureFling
+	GesturePinch
+	GestureRotate
+)
+
+// Gesture phases. Tap and Fling are discrete and only ever End.
+const (
+	GestureBegan = iota
+	GestureChanged
+	GestureEnded
+	GestureCancelled
+)
+
+// Fling directions. +Y is upwards.
+const (
+	SwipeLeft = iota
+	SwipeRight
+	SwipeUp
+	SwipeDown
+)
+
+// IGesture is a gesture recognized on a node. Positions, deltas and
+// velocities are in the node's parent-space so they can be applied
+// directly to the node's position.
+type IGesture interface {
+	Kind() int
+	Phase() int
+	Node() INode
+
+	Position() IPoint
+	// Delta is the movement since the previous Drag event.
+	Delta() IPoint
+	// Velocity is in units per second.
+	Velocity() IPoint
+	// Direction is a SwipeXXX for a Fling.
+	Direction() int
+
+	// Scale is a Pinch's distance relative to where it began.
+	Scale() float32
+	// Rotation is a Rotate's angle, in radians, relative to where it
+	// began.
+	Rotation() float64
+
+	// Taps is 2 for a double tap, 3 for a triple.
+	Taps() int
+}
+
+// IGestureDetector recognizes gestures from the pointer events a node
+// handles. A node forwards its Handle and Update calls and implements
+// IEventReceiver. Handling the press captures the pointer so the node
+// sees the motion and release wherever they are.
+//
+// A press on the node starts every enabled recognizer. The first to
+// recognize its gesture cancels the others, unless the two kinds were
+// marked Simultaneous. Drag and Fling, and Pinch and Rotate, are by
+// default. Recognizers enabled earlier get the first chance.
+//
+// Pinch and Rotate are emulated with a modifier held while pressing.
+// The second pointer is the mirror image of the cursor through the
+// node's origin.
+type IGestureDetector interface {
+	// Recognize enables a GestureXXX kind.
+	Recognize(kind int)
+	Simultaneous(kindA, kindB int)
+	// SetPinchModifier sets the ModXXX that emulates a second pointer.
+	SetPinchModifier(mod int)
+
+	SetListener(listener func(IGesture))
+
+	// Handle returns true while a press on the node is being tracked.
+	Handle(IEvent) bool
+	Update(msPerUpdate float64)
+
+	// Cancel ends any gesture in progress with GestureCancelled.
+	Cancel()
+}
@@ -5,9 +5,17 @@ type IGroup interface {
 	// Children returns the children of current node.
 	// Nodes should override this method for providing any child they contain.
 	Children() []INode
+	// DrawOrder returns the children sorted by sorting layer and then
+	// z-index, back to front. Ties keep the order children were added.
+	DrawOrder() []INode
+	// InvalidateDrawOrder is called when a child's z-index or sorting
+	// layer changes.
+	InvalidateDrawOrder()
 
 	AddChild(INode)
 	PrependChild(INode)
+	InsertChildAt(child INode, index int)
+	RemoveChild(child INode) bool
 
 	GetChildByID(id int) INode
 	GetChildByName(name string) INode
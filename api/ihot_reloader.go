@@ -0,0 +1,27 @@
+package api
+
+import "io/fs"
+
+// IHotReloader watches files, by polling, and reloads them between
+// frames. It is meant for development and is enabled by the
+// Engine.HotReload property.
+type IHotReloader interface {
+	Enabled() bool
+	SetEnabled(enabled bool)
+
+	// Watch calls reload whenever any of the files, within fsys,
+	// change. A file's change is detected by its modification time or
+	// size so files that are embedded never change.
+	Watch(fsys fs.FS, reload func() error, files ...string)
+
+	// Poll checks the files once the poll interval has elapsed.
+	// Nothing is checked while disabled. A reload that fails leaves the
+	// previous resource in place and its error is returned.
+	Poll() []error
+
+	// Check checks the files now regardless of the interval.
+	Check() []error
+
+	// Reloads is the number of reloads that have succeeded.
+	Reloads() int
+}
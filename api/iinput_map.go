@@ -0,0 +1,45 @@
+package api
+
+import "io"
+
+// IInputMap maps keys, mouse buttons and modifiers to named actions,
+// for example "thrust", "fire" or "pause". Actions are queried per
+// update tick rather than switching on raw events.
+type IInputMap interface {
+	// BindKey binds a key (GLFW key code) to action. mods are the
+	// modifier bits that must also be held, zero for none.
+	BindKey(action string, key int, mods int)
+	// BindMouseButton binds a MouseButtonXXX to action.
+	BindMouseButton(action string, button int, mods int)
+	// BindModifier binds a ModXXX, on its own, to action.
+	BindModifier(action string, mod int)
+	// Unbind removes all of action's bindings.
+	Unbind(action string)
+
+	// BindAxis defines an axis from two actions. The axis is -1 when
+	// only negative is held, +1 when only positive is held and 0
+	// otherwise.
+	BindAxis(axis string, negative, positive string)
+
+	// Load replaces all bindings with those read from JSON.
+	Load(r io.Reader) error
+	// Save writes all bindings as JSON.
+	Save(w io.Writer) error
+
+	Actions() []string
+
+	// Handle tracks the raw key and button state from an event.
+	Handle(event IEvent)
+	// Update advances the actions by one tick. The engine calls it
+	// before each update of the scene graph.
+	Update()
+
+	// Pressed is true on the tick the action became active.
+	Pressed(action string) bool
+	// Held is true while the action is active.
+	Held(action string) bool
+	// Released is true on the tick the action became inactive.
+	Released(action string) bool
+
+	Axis(axis string) float64
+}
@@ -0,0 +1,33 @@
+package api
+
+import "io"
+
+// IInputRecorder records the events that reach World.RouteEvents,
+// stamped with the update tick they arrived on, and plays them back
+// at the same ticks. Ticks are relative to when recording or playback
+// began.
+type IInputRecorder interface {
+	// Record begins a new recording at the current tick.
+	Record()
+	Recording() bool
+
+	// Play loads a recording and begins playing it at the current
+	// tick. Live events are still routed while playing.
+	Play(r io.Reader) error
+	// Playing is false once every recorded tick has been played.
+	Playing() bool
+
+	// Stop ends recording or playback.
+	Stop()
+
+	// Save writes the recording in a compact binary form.
+	Save(w io.Writer) error
+
+	// Capture records an event if recording. The World calls it for
+	// every routed event.
+	Capture(event IEvent)
+	// Advance is called by the engine before each update with the
+	// engine's tick. While playing, the events recorded for that tick
+	// are routed.
+	Advance(tick uint64, route func(event IEvent))
+}
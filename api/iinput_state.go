@@ -0,0 +1,31 @@
+package api
+
+// IInputState is a snapshot of the keyboard, mouse buttons and cursor
+// taken once per update tick, along with the previous tick's. A press
+// and release between two ticks reads as down for one tick.
+type IInputState interface {
+	// Handle tracks the raw state from an event.
+	Handle(event IEvent)
+	// Update takes the snapshot. The engine calls it before each update
+	// of the scene graph.
+	Update()
+
+	// IsDown is true while the key (GLFW key code) is down.
+	IsDown(key int) bool
+	// WentDown is true on the tick the key went down.
+	WentDown(key int) bool
+	// WentUp is true on the tick the key went up.
+	WentUp(key int) bool
+
+	// The same for MouseButtonXXX
+	IsButtonDown(button int) bool
+	ButtonWentDown(button int) bool
+	ButtonWentUp(button int) bool
+
+	// Cursor is in device coordinates with +Y upwards.
+	Cursor() (x, y int32)
+	PreviousCursor() (x, y int32)
+	// CursorView is the Cursor mapped to view-space.
+	CursorView() IPoint
+	PreviousCursorView() IPoint
+}
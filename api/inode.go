@@ -31,6 +31,29 @@ type INode interface {
 	IsVisible() bool
 	SetVisible(bool)
 
+	// Opacity, 0->1, multiplies the alpha of the node and its
+	// children. The default is 1.
+	Opacity() float32
+	SetOpacity(opacity float32)
+	// Tint multiplies the colors of the node and its children. The
+	// default is white. SetTint(nil) removes it.
+	Tint() IPalette
+	SetTint(tint IPalette)
+
+	// ZIndex orders a node among its siblings within the same sorting
+	// layer. Higher draws later, on top. The default is 0.
+	ZIndex() int
+	SetZIndex(z int)
+	// SortingLayer names a layer set by INodeManager.SetSortingLayers.
+	SortingLayer() string
+	SetSortingLayer(name string)
+
+	// Tags are free-form labels used by queries, e.g. "enemy".
+	Tags() []string
+	HasTag(tag string) bool
+	AddTag(tag string)
+	RemoveTag(tag string)
+
 	IsDirty() bool
 	SetDirty(dirty bool)
 	// RippleDirty passes the dirty flag downward to children.
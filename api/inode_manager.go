@@ -3,6 +3,9 @@ package api
 // INodeManager manages node on a stack and forms a SceneGraph
 type INodeManager interface {
 	Configure(IWorld) error
+	// Resize rebuilds view-space and applies the viewport once the
+	// World's viewport has changed size.
+	Resize()
 
 	ClearEnabled(bool)
 
@@ -12,13 +15,35 @@ type INodeManager interface {
 	End()
 	Visit(interpolation float64) bool
 
+	// Update first enters the nodes added to live parents since the
+	// last update.
 	Update(msPerUpdate, secPerUpdate float64)
 
+	// IsLive is true when node is in a scene on the stage, that is,
+	// one that has been entered and not yet exited.
+	IsLive(node INode) bool
+	// FindNode looks up a slash-separated path from the root, for
+	// example "Scenes/Game Layer/Ship".
+	FindNode(path string) INode
+
 	PushNode(INode)
 	PopNode() INode
 	ReplaceNode(INode)
 
+	// SetSortingLayers names the sorting layers from back to front.
+	SetSortingLayers(names ...string)
+
+	// RouteEvents hands pointer events to the hit node and its parents,
+	// and focus events to the focused node and its parents, if they
+	// implement IEventReceiver. The registered targets get what's left.
 	RouteEvents(IEvent)
+	// HitTest returns the topmost visible node under the device-space
+	// point, or nil.
+	HitTest(dvx, dvy int32) INode
+
+	// Focus owns keyboard focus. Key, text and gamepad button events
+	// go to the focused node first.
+	Focus() IFocusManager
 
 	RegisterTarget(target INode)
 	UnRegisterTarget(target INode)
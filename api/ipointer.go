@@ -0,0 +1,24 @@
+package api
+
+// IHitPolygon is implemented by nodes whose shape is better described
+// by a polygon than by their bounds. The polygon is in local-space.
+type IHitPolygon interface {
+	HitPolygon() IPolygon
+}
+
+// ICaptureHandler is implemented by nodes that want to see pointer
+// events on the way down to the target, before the target does.
+// Returning true stops the event.
+type ICaptureHandler interface {
+	HandleCapture(IEvent) bool
+}
+
+// IEventReceiver is implemented by nodes that want the events routed
+// along their parent chain, see INodeManager.RouteEvents. Other nodes
+// can still be hit, and so block the nodes below them, but their Handle
+// isn't called. Focusable nodes always receive.
+type IEventReceiver interface {
+	// ReceivesEvents can return false to stop receiving, for example
+	// a disabled button.
+	ReceivesEvents() bool
+}
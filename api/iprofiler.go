@@ -0,0 +1,36 @@
+package api
+
+import "io"
+
+// IProfiler records per-frame phase durations and per node type draw
+// statistics into a ring buffer. Frame indices passed to the query
+// methods are relative to the buffer: 0 is the oldest retained frame.
+type IProfiler interface {
+	Enabled() bool
+	SetEnabled(enabled bool)
+
+	// Capacity is the maximum number of frames retained.
+	Capacity() int
+	Reset()
+
+	BeginFrame()
+	EndFrame()
+
+	// BeginPhase/EndPhase bracket one of the ProfilePhase constants.
+	// A phase bracketed more than once in a frame accumulates.
+	BeginPhase(phase int)
+	EndPhase(phase int)
+
+	// BeginDraw returns a mark that is handed back to EndDraw.
+	BeginDraw() int64
+	EndDraw(nodeType string, mark int64)
+
+	FrameCount() int
+	FrameDuration(index int) int64
+	PhaseDuration(index int, phase int) int64
+	DrawStats(index int, nodeType string) (calls int, duration int64)
+
+	// WriteChromeTrace writes the retained frames in the Chrome trace
+	// event format. Load it with chrome://tracing or Perfetto.
+	WriteChromeTrace(w io.Writer) error
+}
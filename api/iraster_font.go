@@ -1,5 +1,7 @@
 package api
 
+import "io/fs"
+
 // A simple Unicode raster 8x8 font
 // The raw font data was ported from a Rust crate:
 // https://crates.io/crates/font8x8/0.2.3
@@ -7,6 +9,8 @@ package api
 // IRasterFont is the bitmap raster font defined in assets/raster_font.data
 type IRasterFont interface {
 	Initialize(dataFile string, relativePath string) error
+	// InitializeFS reads dataFile from fsys's AssetDir.
+	InitializeFS(fsys fs.FS, dataFile string) error
 
 	// Glyph returns an array of vertices that matches the character
 	Glyph(char byte) []uint8
@@ -0,0 +1,24 @@
+package api
+
+import "image"
+
+// IRasterizer renders primitives into an image without a GPU. It is
+// used by the Software backend. Vertices are x,y,z triples and are
+// transformed by projection * view * model just like the shaders do.
+type IRasterizer interface {
+	Image() *image.NRGBA
+	Clear(r, g, b, a float32)
+
+	// Resize replaces the image with one of the new size.
+	Resize(width, height int)
+
+	SetPointSize(size float32)
+
+	// DrawElements draws count indices using a primitive mode, for
+	// example gl.TRIANGLES or gl.LINE_LOOP.
+	DrawElements(mode int, vertices []float32, indices []uint32, count int, model IMatrix4, color []float32)
+
+	// DrawTexturedTriangles draws triangles whose vertices are x,y,z,s,t.
+	// The texture is sampled (nearest) and multiplied by color.
+	DrawTexturedTriangles(vertices []float32, indices []uint32, texture *image.NRGBA, model IMatrix4, color []float32)
+}
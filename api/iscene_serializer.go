@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// INodeCodec saves and rebuilds one type of node.
+type INodeCodec interface {
+	// Encode returns the node's own properties, for example a shape's
+	// draw style and colors, or nil if it has none. They are written
+	// as JSON.
+	Encode(node INode) (interface{}, error)
+	// Decode builds a node from the properties Encode wrote and adds it
+	// to parent the way the node's constructor does. The name,
+	// transform, visibility, ordering, tags and children are applied
+	// afterwards.
+	Decode(name string, properties json.RawMessage, world IWorld, parent INode) (INode, error)
+}
+
+// ISceneSerializer writes a node subtree as JSON and rebuilds it
+// against a world.
+type ISceneSerializer interface {
+	// Register adds a codec for nodes of the same Go type as sample,
+	// written with the type name kind. Registering a kind again
+	// replaces its codec.
+	Register(kind string, sample INode, codec INodeCodec)
+
+	// Save writes node and its subtree. Every node in it must be of a
+	// registered type.
+	Save(node INode, w io.Writer) error
+	// Load rebuilds a saved subtree under parent and returns its top
+	// node.
+	Load(r io.Reader, world IWorld, parent INode) (INode, error)
+}
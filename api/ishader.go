@@ -1,8 +1,16 @@
 package api
 
+import "io/fs"
+
 // IShader represents a shader program
 type IShader interface {
-	Load(relativePath string) error
+	// Load reads the sources from fsys's ShaderDir and compiles them.
+	Load(fsys fs.FS) error
+	// Reload recompiles from the source files. The current program is
+	// kept if compiling fails.
+	Reload(fsys fs.FS) error
+	// Sources are the vertex and fragment file names, if any.
+	Sources() (vertexSrc, fragmentSrc string)
 	Compile() error
 	Use()
 	Program() uint32
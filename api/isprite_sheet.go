@@ -2,12 +2,20 @@ package api
 
 import (
 	"image"
+	"io/fs"
 )
 
 // ISpriteSheet represents a collection of sprites (aka sub textures)
 type ISpriteSheet interface {
 	Name() string
 	Load(relativePath string, flipped bool)
+	// LoadFS loads the manifest, and its image, from dir within fsys.
+	LoadFS(fsys fs.FS, dir string, flipped bool) error
+	// Reload reads the manifest and image again using the arguments
+	// given to Load.
+	Reload() error
+	// Files are the manifest and image files, within fsys, used by Load.
+	Files() (fsys fs.FS, files []string)
 	SheetImage() *image.NRGBA
 	TextureXYCoords(name string) *[]int
 	TextureSTCoords(name string) *[]float32
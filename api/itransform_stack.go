@@ -5,6 +5,12 @@ type ITransformStack interface {
 	Initialize(IMatrix4)
 	Apply(IMatrix4) IMatrix4
 	ApplyAffine(IAffineTransform) IMatrix4
+	// Save and Restore include the tint.
 	Save()
 	Restore()
+
+	// ApplyTint multiplies an RGBA tint into the current tint.
+	ApplyTint(r, g, b, a float32)
+	// Tint is the current RGBA tint. It changes with the stack.
+	Tint() []float32
 }
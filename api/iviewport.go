@@ -0,0 +1,26 @@
+package api
+
+// IViewport maps the virtual resolution onto the device (i.e. the
+// framebuffer) according to a scaling policy.
+type IViewport interface {
+	Policy() string
+
+	// Resize recomputes the viewport for a new device size.
+	Resize(deviceWidth, deviceHeight int)
+
+	// Apply sets the GL viewport.
+	Apply()
+
+	// Dimensions are in device pixels with the origin at bottom-left.
+	Dimensions() (x, y, width, height int)
+
+	// ViewSize is the size of the projection in view units.
+	ViewSize() (width, height float32)
+
+	// DeviceToProjection maps device coordinates (+Y up) into
+	// projection units.
+	DeviceToProjection(dvx, dvy float32) (x, y float32)
+
+	// ProjectionToDevice is the inverse of DeviceToProjection.
+	ProjectionToDevice(x, y float32) (dvx, dvy float32)
+}
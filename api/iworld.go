@@ -1,6 +1,8 @@
 package api
 
 import (
+	"io/fs"
+
 	"github.com/wdevore/Ranger-Go-IGE/engine/configuration"
 )
 
@@ -11,9 +13,15 @@ type IWorld interface {
 	End()
 	RelativePath() string
 
+	// FS is the file system every loader reads from. By default it is
+	// the directory at RelativePath.
+	FS() fs.FS
+
 	NodeManager() INodeManager
 	Properties() *configuration.Properties
-	PropertiesOverride(configFiel string)
+	// PropertiesOverride merges a config file, read from FS(), on top
+	// of the current properties.
+	PropertiesOverride(configFile string) error
 
 	Root() INode
 	Underlay() INode
@@ -24,6 +32,29 @@ type IWorld interface {
 
 	RouteEvents(event IEvent)
 
+	// EventQueue holds the display's events until the engine routes
+	// them, once per frame before updating.
+	EventQueue() IEventQueue
+
+	// Input maps the routed events to named actions.
+	Input() IInputMap
+	// InputState is the keyboard, mouse and cursor state of the current
+	// and previous update ticks.
+	InputState() IInputState
+
+	// Gamepads are polled by the engine each frame and their events
+	// routed like any other. The OpenGL backend sets a GLFW source,
+	// others have none until one is set, for example a mock.
+	Gamepads() IGamepads
+
+	// Recorder records and plays back routed events.
+	Recorder() IInputRecorder
+
+	// Resize updates the viewport, projection and view-space for
+	// a new device (framebuffer) size.
+	Resize(width, height int)
+	Viewport() IViewport
+
 	Projection() IMatrix4
 	Viewspace() IMatrix4
 	InvertedViewspace() IMatrix4
@@ -41,4 +72,20 @@ type IWorld interface {
 
 	AvgRender() float64
 	SetAvgRender(float64)
+
+	// DebugController pauses, single steps and time scales updates.
+	DebugController() IDebugController
+
+	// Profiler is available after Configure()
+	Profiler() IProfiler
+
+	// HotReloader is available after Configure()
+	HotReloader() IHotReloader
+
+	// Assets manages atlases, sprite sheets, fonts and sounds.
+	Assets() IAssetManager
+
+	// Rasterizer is only available, after Configure(), when using
+	// the Software backend. Otherwise it is nil.
+	Rasterizer() IRasterizer
 }
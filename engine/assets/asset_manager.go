@@ -0,0 +1,269 @@
+// Package assets reference counts and loads the World's assets.
+package assets
+
+import (
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+const (
+	decoding = iota
+	ready
+)
+
+type asset struct {
+	assetType string
+	name      string
+	refs      int
+	state     int
+
+	// Written by the decoding goroutine before done is closed.
+	value interface{}
+	err   error
+	done  chan struct{}
+}
+
+// assetManager is only used from the main thread. Just the loaders'
+// Decode runs on other goroutines.
+type assetManager struct {
+	loaders map[string]api.IAssetLoader
+	assets  map[string]*asset
+
+	// Preloads in the order requested
+	pending []*asset
+	done    int
+	total   int
+}
+
+// NewAssetManager creates an asset manager without any loaders.
+func NewAssetManager() api.IAssetManager {
+	o := new(assetManager)
+	o.loaders = make(map[string]api.IAssetLoader)
+	o.assets = make(map[string]*asset)
+	return o
+}
+
+func key(assetType, name string) string {
+	return assetType + ":" + name
+}
+
+func (m *assetManager) RegisterLoader(assetType string, loader api.IAssetLoader) {
+	m.loaders[assetType] = loader
+}
+
+func (m *assetManager) loader(assetType string) (api.IAssetLoader, error) {
+	loader, ok := m.loaders[assetType]
+	if !ok {
+		return nil, fmt.Errorf("AssetManager: no loader for type '%s'", assetType)
+	}
+	return loader, nil
+}
+
+func (m *assetManager) Load(assetType, name, source string) (interface{}, error) {
+	loader, err := m.loader(assetType)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := m.assets[key(assetType, name)]
+	if ok {
+		// Finish a preload early rather than waiting for Update.
+		if a.state != ready {
+			<-a.done
+			err = m.upload(loader, a)
+			if err != nil {
+				return nil, err
+			}
+		}
+		a.refs++
+		return a.value, nil
+	}
+
+	value, err := loader.Decode(name, source)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loader.Upload(value)
+	if err != nil {
+		return nil, err
+	}
+
+	m.assets[key(assetType, name)] = &asset{
+		assetType: assetType,
+		name:      name,
+		refs:      1,
+		state:     ready,
+		value:     value,
+	}
+
+	return value, nil
+}
+
+func (m *assetManager) Add(assetType, name string, value interface{}) {
+	a, ok := m.assets[key(assetType, name)]
+	if ok {
+		a.refs++
+		return
+	}
+
+	m.assets[key(assetType, name)] = &asset{
+		assetType: assetType,
+		name:      name,
+		refs:      1,
+		state:     ready,
+		value:     value,
+	}
+}
+
+func (m *assetManager) Get(assetType, name string) interface{} {
+	a, ok := m.assets[key(assetType, name)]
+	if !ok || a.state != ready {
+		return nil
+	}
+	return a.value
+}
+
+func (m *assetManager) Acquire(assetType, name string) interface{} {
+	a, ok := m.assets[key(assetType, name)]
+	if !ok || a.state != ready {
+		return nil
+	}
+	a.refs++
+	return a.value
+}
+
+func (m *assetManager) Release(assetType, name string) {
+	a, ok := m.assets[key(assetType, name)]
+	if !ok {
+		return
+	}
+
+	a.refs--
+	if a.refs > 0 {
+		return
+	}
+
+	delete(m.assets, key(assetType, name))
+
+	// A pending preload is discarded by Update once decoded.
+	if a.state != ready {
+		return
+	}
+
+	loader, err := m.loader(assetType)
+	if err == nil {
+		loader.Release(a.value)
+	}
+}
+
+func (m *assetManager) RefCount(assetType, name string) int {
+	a, ok := m.assets[key(assetType, name)]
+	if !ok {
+		return 0
+	}
+	return a.refs
+}
+
+func (m *assetManager) Preload(assetType, name, source string) {
+	m.total++
+
+	a, ok := m.assets[key(assetType, name)]
+	if ok {
+		// Already loaded or queued so it only needs a reference.
+		a.refs++
+		if a.state == ready {
+			m.done++
+		} else {
+			m.pending = append(m.pending, a)
+		}
+		return
+	}
+
+	a = &asset{
+		assetType: assetType,
+		name:      name,
+		refs:      1,
+		state:     decoding,
+		done:      make(chan struct{}),
+	}
+	m.assets[key(assetType, name)] = a
+	m.pending = append(m.pending, a)
+
+	loader, err := m.loader(assetType)
+	if err != nil {
+		a.err = err
+		close(a.done)
+		return
+	}
+
+	go func() {
+		a.value, a.err = loader.Decode(name, source)
+		close(a.done)
+	}()
+}
+
+func (m *assetManager) Update() []error {
+	var errs []error
+
+	pending := m.pending[:0]
+
+	for _, a := range m.pending {
+		if a.state != ready {
+			select {
+			case <-a.done:
+			default:
+				pending = append(pending, a)
+				continue
+			}
+		}
+
+		m.done++
+
+		if a.state == ready {
+			continue
+		}
+
+		// Released while still decoding
+		if m.assets[key(a.assetType, a.name)] != a {
+			continue
+		}
+
+		loader, err := m.loader(a.assetType)
+		if err == nil {
+			err = m.upload(loader, a)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	m.pending = pending
+
+	return errs
+}
+
+// upload finishes a decoded asset. A failed asset is removed.
+func (m *assetManager) upload(loader api.IAssetLoader, a *asset) error {
+	err := a.err
+	if err == nil {
+		err = loader.Upload(a.value)
+	}
+
+	if err != nil {
+		delete(m.assets, key(a.assetType, a.name))
+		return fmt.Errorf("AssetManager: %s '%s': %v", a.assetType, a.name, err)
+	}
+
+	a.state = ready
+	return nil
+}
+
+func (m *assetManager) Progress() (done, total int) {
+	return m.done, m.total
+}
+
+func (m *assetManager) Loaded() bool {
+	return m.done == m.total
+}
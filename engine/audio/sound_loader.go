@@ -0,0 +1,43 @@
+package audio
+
+import (
+	"encoding/json"
+	"io/fs"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+type soundLoader struct {
+	fsys fs.FS
+}
+
+// NewSoundLoader creates an asset loader for sfxr sounds. The source is
+// the path, within fsys, to a sfxr json file and the asset is its
+// IGeneratorValues.
+func NewSoundLoader(fsys fs.FS) api.IAssetLoader {
+	o := new(soundLoader)
+	o.fsys = fsys
+	return o
+}
+
+func (l *soundLoader) Decode(name, source string) (interface{}, error) {
+	bytes, err := fs.ReadFile(l.fsys, source)
+	if err != nil {
+		return nil, err
+	}
+
+	sfxrJ := &SfxrJSON{}
+	err = json.Unmarshal(bytes, sfxrJ)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIntervalValues(sfxrJ), nil
+}
+
+func (l *soundLoader) Upload(asset interface{}) error {
+	return nil
+}
+
+func (l *soundLoader) Release(asset interface{}) {
+}
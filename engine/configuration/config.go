@@ -2,12 +2,22 @@ package configuration
 
 type engineJSON struct {
 	Enabled          bool
-	LoopFor          int
+	Backend          string // "OpenGL", "Headless", "Software"
+	LoopFor          int    // Run limit, -1 = forever
+	LoopUnit         string // "Frames", "Updates", "Seconds"
+	LoopExitCode     int    // Exit code used when the run limit is reached
 	ShowConfig       bool
 	ShowGLInfo       bool
 	ShowMonitorInfo  bool
 	ShowTimingInfo   bool
 	ShowJoystickInfo bool
+	GamepadDeadZone  float32 // Stick and trigger dead-zone within [0, 1)
+	Profile          bool    // Enables the frame profiler at startup
+	ProfileFrames    int     // Profiler ring buffer size, 0 = default
+	HotReload        bool    // Reloads changed shaders, sprite sheets and config
+	HotReloadPoll    int     // Milliseconds between checks for changed files
+	RecordInput      string  // File the routed events are recorded to on End
+	PlayInput        string  // Recording played back from the first update
 	GLMajorVersion   int
 	GLMinorVersion   int
 	FPSRate          float64
@@ -45,8 +55,10 @@ type windowJSON struct {
 	BackgroundColor colorJSON
 	VirtualRes      dimensionJSON
 	DeviceRes       dimensionJSON
+	ScalePolicy     string // "None", "Stretch", "Letterbox", "PixelPerfect", "Expand"
+	Resizable       bool
 	FullScreen      bool
-	Orientation     string
+	Orientation     string // "Landscape", "Portrait"
 	ViewScale       float64
 	Position        int2DCoordJSON
 	Title           string
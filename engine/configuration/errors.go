@@ -0,0 +1,47 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SourceError is returned when a configuration source, for example a
+// file, an environment variable or a command-line flag, can't be read
+// or parsed.
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string {
+	return "config: " + e.Source + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError describes a property that has an invalid value.
+type FieldError struct {
+	Field  string // For example "Engine.UPSRate"
+	Value  interface{}
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("config: %s = %v: %s", e.Field, e.Value, e.Reason)
+}
+
+// ValidationError collects every FieldError found during validation.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
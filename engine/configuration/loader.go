@@ -0,0 +1,178 @@
+package configuration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Properties are layered in this order, each layer overriding the last:
+//   1) engine defaults (engine/configuration/config.json)
+//   2) game config (the overrides file given to engine.Construct)
+//   3) environment variables
+//   4) command-line flags
+
+const (
+	// EnvPrefix prefixes environment variables that override a property.
+	// The path is separated by "_", for example:
+	// RANGER_ENGINE_UPSRATE=30 or RANGER_WINDOW_DEVICERES_WIDTH=800
+	EnvPrefix = "RANGER_"
+
+	// FlagPrefix prefixes command-line flags that override a property.
+	// The path is separated by ".", for example:
+	// -ranger.Engine.UPSRate=30 or --ranger.Window.DeviceRes.Width=800
+	FlagPrefix = "ranger."
+)
+
+var errUnknownProperty = errors.New("unknown property")
+
+// LoadFile merges a JSON file on top of the current values.
+// Unknown keys are reported as errors.
+func (p *Properties) LoadFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return &SourceError{Source: "file " + file, Err: err}
+	}
+
+	defer f.Close()
+
+	return p.decode(f, file)
+}
+
+// LoadFS is LoadFile for a file within fsys.
+func (p *Properties) LoadFS(fsys fs.FS, file string) error {
+	f, err := fsys.Open(file)
+	if err != nil {
+		return &SourceError{Source: "file " + file, Err: err}
+	}
+
+	defer f.Close()
+
+	return p.decode(f, file)
+}
+
+func (p *Properties) decode(r io.Reader, file string) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(p)
+	if err != nil {
+		return &SourceError{Source: "file " + file, Err: err}
+	}
+
+	return nil
+}
+
+// ApplyEnv applies any EnvPrefix variables found in environ, which is
+// in the form returned by os.Environ(). Variables that don't name a
+// property are ignored, other tools share the prefix.
+func (p *Properties) ApplyEnv(environ []string) error {
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, EnvPrefix) {
+			continue
+		}
+
+		eq := strings.Index(kv, "=")
+		if eq < 0 {
+			continue
+		}
+
+		name := kv[:eq]
+		path := strings.Split(strings.TrimPrefix(name, EnvPrefix), "_")
+
+		err := p.set(path, kv[eq+1:])
+		if errors.Is(err, errUnknownProperty) {
+			fmt.Println("Configuration: ignoring env ", name)
+			continue
+		}
+		if err != nil {
+			return &SourceError{Source: "env " + name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// ApplyFlags applies any FlagPrefix arguments in the form
+// -ranger.Path=value. All other arguments are ignored so the game is
+// free to use the flag package for its own flags.
+func (p *Properties) ApplyFlags(args []string) error {
+	for _, arg := range args {
+		name := strings.TrimLeft(arg, "-")
+		if name == arg || !strings.HasPrefix(name, FlagPrefix) {
+			continue
+		}
+
+		eq := strings.Index(name, "=")
+		if eq < 0 {
+			return &SourceError{Source: "flag " + arg, Err: errors.New("expected -" + FlagPrefix + "Path=value")}
+		}
+
+		path := strings.Split(strings.TrimPrefix(name[:eq], FlagPrefix), ".")
+
+		err := p.set(path, name[eq+1:])
+		if err != nil {
+			return &SourceError{Source: "flag " + arg, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// Set assigns a value to the property at a "." separated path, for
+// example Set("Window.DeviceRes.Width", "800"). Names are case-insensitive.
+func (p *Properties) Set(path, value string) error {
+	return p.set(strings.Split(path, "."), value)
+}
+
+func (p *Properties) set(path []string, value string) error {
+	v := reflect.ValueOf(p).Elem()
+
+	for _, name := range path {
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("%w %q", errUnknownProperty, strings.Join(path, "."))
+		}
+
+		field := v.FieldByNameFunc(func(n string) bool {
+			return strings.EqualFold(n, name)
+		})
+		if !field.IsValid() {
+			return fmt.Errorf("%w %q", errUnknownProperty, strings.Join(path, "."))
+		}
+
+		v = field
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("property %q isn't a single value", strings.Join(path, "."))
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package configuration
+
+import (
+	"io/fs"
+	"path"
+)
+
+// These mirror the api package's constants. The api package imports
+// configuration so it can't be imported here.
+var (
+	backends      = []string{"", "OpenGL", "Headless", "Software"}
+	loopUnits     = []string{"", "Frames", "Updates", "Seconds"}
+	scalePolicies = []string{"", "None", "Stretch", "Letterbox", "PixelPerfect", "Expand"}
+	orientations  = []string{"", "Landscape", "Portrait"}
+)
+
+const shaderDir = "engine/assets/shaders"
+
+// Validate checks the property values. fsys is the engine's file system
+// and is used to check that the shader files exist. All problems are
+// returned together as a *ValidationError.
+func (p *Properties) Validate(fsys fs.FS) error {
+	v := &ValidationError{}
+
+	check := func(ok bool, field string, value interface{}, reason string) {
+		if !ok {
+			v.Fields = append(v.Fields, &FieldError{Field: field, Value: value, Reason: reason})
+		}
+	}
+
+	e := p.Engine
+	check(oneOf(e.Backend, backends), "Engine.Backend", e.Backend, "unknown backend")
+	check(e.UPSRate > 0, "Engine.UPSRate", e.UPSRate, "must be greater than zero")
+	check(e.FPSRate > 0, "Engine.FPSRate", e.FPSRate, "must be greater than zero")
+	check(oneOf(e.LoopUnit, loopUnits), "Engine.LoopUnit", e.LoopUnit, "unknown unit")
+	check(e.ProfileFrames >= 0, "Engine.ProfileFrames", e.ProfileFrames, "can't be negative")
+	check(e.GamepadDeadZone >= 0.0 && e.GamepadDeadZone < 1.0, "Engine.GamepadDeadZone", e.GamepadDeadZone, "must be within [0, 1)")
+	check(e.HotReloadPoll >= 0, "Engine.HotReloadPoll", e.HotReloadPoll, "can't be negative")
+	if e.Backend == "" || e.Backend == "OpenGL" {
+		check(e.GLMajorVersion > 0, "Engine.GLMajorVersion", e.GLMajorVersion, "must be greater than zero")
+	}
+
+	w := p.Window
+	check(w.DeviceRes.Width > 0, "Window.DeviceRes.Width", w.DeviceRes.Width, "must be greater than zero")
+	check(w.DeviceRes.Height > 0, "Window.DeviceRes.Height", w.DeviceRes.Height, "must be greater than zero")
+	check(oneOf(w.ScalePolicy, scalePolicies), "Window.ScalePolicy", w.ScalePolicy, "unknown policy")
+	if w.ScalePolicy != "" && w.ScalePolicy != "None" {
+		check(w.VirtualRes.Width > 0, "Window.VirtualRes.Width", w.VirtualRes.Width, "must be greater than zero")
+		check(w.VirtualRes.Height > 0, "Window.VirtualRes.Height", w.VirtualRes.Height, "must be greater than zero")
+	}
+	check(oneOf(w.Orientation, orientations), "Window.Orientation", w.Orientation, "unknown orientation")
+	check(w.ViewScale > 0, "Window.ViewScale", w.ViewScale, "must be greater than zero")
+	check(unitColor(w.ClearColor), "Window.ClearColor", w.ClearColor, "components must be within [0, 1]")
+	check(unitColor(w.BackgroundColor), "Window.BackgroundColor", w.BackgroundColor, "components must be within [0, 1]")
+
+	c := p.Camera
+	check(c.Depth.Near < c.Depth.Far, "Camera.Depth", c.Depth, "Near must be less than Far")
+
+	s := p.Shaders
+	shaderFiles := []struct {
+		field, file string
+	}{
+		{"Shaders.MonoVertexShaderFile", s.MonoVertexShaderFile},
+		{"Shaders.MonoFragmentShaderFile", s.MonoFragmentShaderFile},
+		{"Shaders.DynamicPixelVertexShaderFile", s.DynamicPixelVertexShaderFile},
+		{"Shaders.DynamicPixelFragmentShaderFile", s.DynamicPixelFragmentShaderFile},
+		{"Shaders.TextureVertexShaderFile", s.TextureVertexShaderFile},
+		{"Shaders.TextureFragmentShaderFile", s.TextureFragmentShaderFile},
+	}
+	for _, sf := range shaderFiles {
+		_, err := fs.Stat(fsys, path.Join(shaderDir, sf.file))
+		check(sf.file != "" && err == nil, sf.field, sf.file, "shader file not found in "+shaderDir)
+	}
+
+	if len(v.Fields) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+func oneOf(value string, values []string) bool {
+	for _, s := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+func unitColor(c colorJSON) bool {
+	for _, f := range []float32{c.R, c.G, c.B, c.A} {
+		if f < 0.0 || f > 1.0 {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package display
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -10,6 +11,9 @@ import (
 	"github.com/wdevore/Ranger-Go-IGE/engine/io"
 )
 
+// profileTraceFile is written to the working directory.
+const profileTraceFile = "profile_trace.json"
+
 // GlfwDisplay glfw window
 type GlfwDisplay struct {
 	engine api.IEngine
@@ -17,6 +21,10 @@ type GlfwDisplay struct {
 
 	mouseButtonDown bool
 	xpos, ypos      float64
+	clicks          api.IClickCounter
+
+	// Reused by the callbacks. The queue keeps copies.
+	event *io.Event
 
 	quitTriggered bool
 	polygonMode   bool
@@ -34,6 +42,8 @@ func NewDisplay(engine api.IEngine) *GlfwDisplay {
 	o.clearMask = gl.COLOR_BUFFER_BIT
 	o.polygonMode = false
 	o.clearStyle = 1 // default to single color
+	o.event = io.NewEvent()
+	o.clicks = io.NewClickCounter(io.DoubleClickInterval, io.DoubleClickDistance)
 	return o
 }
 
@@ -120,25 +130,43 @@ func (g *GlfwDisplay) initGLFW(world api.IWorld) error {
 	glfw.WindowHint(glfw.ContextVersionMajor, ep.GLMajorVersion)
 	glfw.WindowHint(glfw.ContextVersionMinor, ep.GLMinorVersion)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-	glfw.WindowHint(glfw.Resizable, glfw.False)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 
-	wp := world.Properties().Window
+	wp := &world.Properties().Window
+
+	if wp.Resizable {
+		glfw.WindowHint(glfw.Resizable, glfw.True)
+	} else {
+		glfw.WindowHint(glfw.Resizable, glfw.False)
+	}
+
+	// Full screen takes over the primary monitor at its current video
+	// mode, which then becomes the device resolution.
+	var monitor *glfw.Monitor
+	if wp.FullScreen {
+		monitor = glfw.GetPrimaryMonitor()
+		mode := monitor.GetVideoMode()
+		wp.DeviceRes.Width = mode.Width
+		wp.DeviceRes.Height = mode.Height
+	}
+
 	// Create a GLFWwindow object that we can use for GLFW's functions
 	g.window, err = glfw.CreateWindow(
 		wp.DeviceRes.Width, wp.DeviceRes.Height,
 		wp.Title,
-		nil, nil)
+		monitor, nil)
 
 	if err != nil {
 		glfw.Terminate()
 		return errors.New("Failed to create GLFW window")
 	}
 
-	g.window.SetSizeCallback(g.framebufferSizeCallback)
+	g.window.SetFramebufferSizeCallback(g.framebufferSizeCallback)
 	// g.window.SetUserPointer()
 
-	g.window.SetPos(wp.Position.X, wp.Position.Y)
+	if !wp.FullScreen {
+		g.window.SetPos(wp.Position.X, wp.Position.Y)
+	}
 
 	g.window.MakeContextCurrent()
 
@@ -159,6 +187,10 @@ func (g *GlfwDisplay) initGLFW(world api.IWorld) error {
 	}
 
 	g.window.SetKeyCallback(g.keyCallback)
+	g.window.SetCharCallback(g.charCallback)
+
+	// Gamepads are polled rather than using callbacks.
+	world.Gamepads().SetSource(newGlfwGamepadSource(ep.ShowJoystickInfo))
 
 	// Mouse events
 	g.window.SetMouseButtonCallback(g.mouseButtonCallback)
@@ -209,16 +241,16 @@ func (g *GlfwDisplay) initGL(world api.IWorld) error {
 	return nil
 }
 
-var event = io.NewEvent()
-
 func (g *GlfwDisplay) keyCallback(glfwW *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
 	// fmt.Println("key pressed ", key)
+	event := g.event
+	event.Reset()
 	event.SetType(api.IOTypeKeyboard)
-	event.SetKeyCode(uint32(scancode))
-	event.SetKeyScan(uint32(key))
+	event.SetKeyCode(uint32(key))
+	event.SetKeyScan(uint32(scancode))
 	event.SetState(uint32(action))
 	event.SetKeyMotif(uint32(mods))
-	g.engine.World().RouteEvents(event)
+	g.post(event)
 
 	if action == glfw.Press {
 		switch key {
@@ -241,42 +273,153 @@ func (g *GlfwDisplay) keyCallback(glfwW *glfw.Window, key glfw.Key, scancode int
 				gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
 			}
 			g.pointMode = !g.pointMode
+		// Debug controller: F5 = pause/resume, F6 = single update step,
+		// F7 = slower, F8 = faster, F9 = normal speed
+		case glfw.KeyF5:
+			g.engine.DebugController().TogglePause()
+		case glfw.KeyF6:
+			g.engine.DebugController().RequestStep()
+		case glfw.KeyF7:
+			g.engine.DebugController().ScaleDown()
+		case glfw.KeyF8:
+			g.engine.DebugController().ScaleUp()
+		case glfw.KeyF9:
+			g.engine.DebugController().ResetTimeScale()
+		// Profiler: F10 = enable/disable, F11 = export Chrome trace
+		case glfw.KeyF10:
+			profiler := g.engine.Profiler()
+			profiler.SetEnabled(!profiler.Enabled())
+			fmt.Println("Profiler: enabled ", profiler.Enabled())
+		case glfw.KeyF11:
+			g.writeProfileTrace()
 		}
 	}
 }
 
+// Text input events
+func (g *GlfwDisplay) charCallback(glfwW *glfw.Window, char rune) {
+	event := g.event
+	event.Reset()
+	event.SetType(api.IOTypeText)
+	event.SetRune(char)
+	event.SetKeyMotif(g.mods())
+
+	g.post(event)
+}
+
+// post stamps and queues an event. The engine routes the queue once
+// polling is done.
+func (g *GlfwDisplay) post(event api.IEvent) {
+	event.SetTimestamp(g.engine.Clock().Now())
+	g.engine.World().EventQueue().Post(event)
+}
+
+// mods reads the modifier keys for events whose callbacks don't
+// provide them.
+func (g *GlfwDisplay) mods() uint32 {
+	held := func(left, right glfw.Key) bool {
+		return g.window.GetKey(left) == glfw.Press || g.window.GetKey(right) == glfw.Press
+	}
+
+	mods := uint32(0)
+	if held(glfw.KeyLeftShift, glfw.KeyRightShift) {
+		mods |= api.ModShift
+	}
+	if held(glfw.KeyLeftControl, glfw.KeyRightControl) {
+		mods |= api.ModControl
+	}
+	if held(glfw.KeyLeftAlt, glfw.KeyRightAlt) {
+		mods |= api.ModAlt
+	}
+	if held(glfw.KeyLeftSuper, glfw.KeyRightSuper) {
+		mods |= api.ModSuper
+	}
+	return mods
+}
+
+// devicePosition is the cursor position with +Y upwards.
+func (g *GlfwDisplay) devicePosition() (x, y int32) {
+	dvr := g.engine.World().Properties().Window.DeviceRes
+
+	// The cursor is in window coordinates but, once resized, the device
+	// resolution is the framebuffer's which is larger on HiDPI displays.
+	xpos, ypos := g.xpos, g.ypos
+	width, height := g.window.GetSize()
+	if width > 0 && height > 0 {
+		xpos *= float64(dvr.Width) / float64(width)
+		ypos *= float64(dvr.Height) / float64(height)
+	}
+
+	// Because OpenGL's +Y axis is upwards we need the mouse's +Y movement
+	// to be the same as OpenGL's, which means we need to flip it.
+	return int32(xpos), int32(dvr.Height) - int32(ypos)
+}
+
+func (g *GlfwDisplay) writeProfileTrace() {
+	file, err := os.Create(profileTraceFile)
+	if err != nil {
+		fmt.Println("Profiler: ", err)
+		return
+	}
+	defer file.Close()
+
+	err = g.engine.Profiler().WriteChromeTrace(file)
+	if err != nil {
+		fmt.Println("Profiler: ", err)
+		return
+	}
+	fmt.Println("Profiler: trace written to ", profileTraceFile)
+}
+
 // Mouse button events
 func (g *GlfwDisplay) mouseButtonCallback(glfwW *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
 	// fmt.Println("mouseButtonCallback ", button, ", ", action, ", ", mods)
-	if action == glfw.Press && button == glfw.MouseButton1 {
-		event.SetButton(1)
-		g.mouseButtonDown = true
+	event := g.event
+	event.Reset()
+
+	g.xpos, g.ypos = glfwW.GetCursorPos()
+	mx, my := g.devicePosition()
+	event.SetMousePosition(mx, my)
+
+	// GLFW's buttons start at zero, the event's at MouseButtonLeft.
+	eventButton := uint8(button) + 1
+	event.SetButton(eventButton)
+	if action == glfw.Press {
 		event.SetType(api.IOTypeMouseButtonDown)
+		event.SetClicks(g.clicks.Press(eventButton, mx, my, g.engine.Clock().Now()))
 	} else {
-		event.SetButton(0)
 		event.SetType(api.IOTypeMouseButtonUp)
-		g.mouseButtonDown = false
+		event.SetClicks(g.clicks.Clicks(eventButton))
 	}
-	g.xpos, g.ypos = glfwW.GetCursorPos()
-	dvr := g.engine.World().Properties().Window.DeviceRes
-	event.SetMousePosition(int32(g.xpos), int32(dvr.Height)-int32(g.ypos))
+	if button == glfw.MouseButton1 {
+		g.mouseButtonDown = action == glfw.Press
+	}
+
 	event.SetState(uint32(action))
 	event.SetKeyMotif(uint32(mods))
 
-	g.engine.World().RouteEvents(event)
+	g.post(event)
 }
 
-// Mouse wheel events
+// Mouse wheel and trackpad scrolling events
 func (g *GlfwDisplay) scrollCallback(glfwW *glfw.Window, xoff float64, yoff float64) {
 	// fmt.Println("scrollCallback")
+	event := g.event
+	event.Reset()
 	event.SetType(api.IOTypeMouseWheel)
+	event.SetScroll(xoff, yoff)
+	// Kept for handlers written before scroll offsets were available.
 	event.SetMouseRelMovement(int32(xoff), int32(yoff))
+	event.SetMousePosition(g.devicePosition())
+	event.SetKeyMotif(g.mods())
 
-	g.engine.World().RouteEvents(event)
+	g.post(event)
 }
 
 // Mouse motion events
 func (g *GlfwDisplay) cursorPosCallback(glfwW *glfw.Window, xpos float64, ypos float64) {
+	event := g.event
+	event.Reset()
 	event.SetType(api.IOTypeMouseMotion)
 
 	if g.mouseButtonDown && (g.xpos != xpos || g.ypos != ypos) {
@@ -286,14 +429,13 @@ func (g *GlfwDisplay) cursorPosCallback(glfwW *glfw.Window, xpos float64, ypos f
 	}
 	g.xpos = xpos
 	g.ypos = ypos
-	// Because OpenGL's +Y axis is upwards we need the mouse's +Y movement
-	// to be the same as OpenGL's, which means we need to flip it.
-	dvr := g.engine.World().Properties().Window.DeviceRes
-	event.SetMousePosition(int32(g.xpos), int32(dvr.Height)-int32(g.ypos))
+	event.SetMousePosition(g.devicePosition())
+	event.SetKeyMotif(g.mods())
 
-	g.engine.World().RouteEvents(event)
+	g.post(event)
 }
 
 func (g *GlfwDisplay) framebufferSizeCallback(glfwW *glfw.Window, width int, height int) {
-	fmt.Printf("Framebuffer re-size: %d x %d", width, height)
+	fmt.Printf("Framebuffer re-size: %d x %d\n", width, height)
+	g.engine.World().Resize(width, height)
 }
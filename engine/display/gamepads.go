@@ -0,0 +1,76 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// glfwGamepadSource reads joysticks that have a gamepad mapping. Others
+// aren't reported. Additional SDL mappings can be added with
+// glfw.UpdateGamepadMappings.
+type glfwGamepadSource struct {
+	showInfo bool
+	present  map[glfw.Joystick]bool
+}
+
+func newGlfwGamepadSource(showInfo bool) *glfwGamepadSource {
+	o := new(glfwGamepadSource)
+	o.showInfo = showInfo
+	o.present = make(map[glfw.Joystick]bool)
+	return o
+}
+
+func (s *glfwGamepadSource) Gamepads() []int {
+	ids := []int{}
+
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if !joy.Present() {
+			delete(s.present, joy)
+			continue
+		}
+
+		if !s.present[joy] {
+			s.present[joy] = true
+			if s.showInfo {
+				s.printInfo(joy)
+			}
+		}
+
+		if joy.IsGamepad() {
+			ids = append(ids, int(joy))
+		}
+	}
+
+	return ids
+}
+
+func (s *glfwGamepadSource) printInfo(joy glfw.Joystick) {
+	fmt.Println("---------------------------- Joystick Info ---------------------------------------")
+	fmt.Printf("Joystick %d: %s\n", joy, joy.GetName())
+	fmt.Printf("GUID: %s\n", joy.GetGUID())
+	if joy.IsGamepad() {
+		fmt.Printf("Gamepad mapping: %s\n", joy.GetGamepadName())
+	} else {
+		fmt.Println("Gamepad mapping: none, it is ignored")
+	}
+	fmt.Println("-------------------------------------------------------------------")
+}
+
+func (s *glfwGamepadSource) Name(id int) string {
+	return glfw.Joystick(id).GetGamepadName()
+}
+
+func (s *glfwGamepadSource) State(id int, buttons []bool, axes []float32) bool {
+	state := glfw.Joystick(id).GetGamepadState()
+	if state == nil {
+		return false
+	}
+
+	for i, action := range state.Buttons {
+		buttons[i] = action == glfw.Press
+	}
+	copy(axes, state.Axes[:])
+
+	return true
+}
@@ -0,0 +1,67 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// NullDisplay is a display that has no window and issues no GL calls.
+// It is used by the Headless backend for running scenes in tests and
+// on machines without a GPU.
+type NullDisplay struct {
+	engine api.IEngine
+
+	closed bool
+
+	clearColor [4]float32
+}
+
+// NewNullDisplay creates a new headless display
+func NewNullDisplay(engine api.IEngine) *NullDisplay {
+	o := new(NullDisplay)
+	o.engine = engine
+	return o
+}
+
+// Initialize does nothing other than report that it is headless.
+func (n *NullDisplay) Initialize(world api.IWorld) error {
+	fmt.Println("Initializing Null display (headless)...")
+	return nil
+}
+
+// Closed checks the display's close status
+func (n *NullDisplay) Closed() bool {
+	return n.closed
+}
+
+// Close marks the display as closed which will stop the engine loop.
+func (n *NullDisplay) Close() {
+	n.closed = true
+}
+
+// Poll does nothing as there are no OS events to pump.
+func (n *NullDisplay) Poll() {
+}
+
+// Shutdown does nothing.
+func (n *NullDisplay) Shutdown() {
+}
+
+// SetClearColor captures the background clear color
+func (n *NullDisplay) SetClearColor(rc, gc, bc, ac float32) {
+	n.clearColor = [4]float32{rc, gc, bc, ac}
+}
+
+// ClearColor returns the captured clear color
+func (n *NullDisplay) ClearColor() (rc, gc, bc, ac float32) {
+	return n.clearColor[0], n.clearColor[1], n.clearColor[2], n.clearColor[3]
+}
+
+// Pre does nothing.
+func (n *NullDisplay) Pre() {
+}
+
+// Swap does nothing.
+func (n *NullDisplay) Swap() {
+}
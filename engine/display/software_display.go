@@ -0,0 +1,35 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// SoftwareDisplay is a windowless display used by the Software backend.
+// Rendering goes to the World's rasterizer image instead of a GL
+// framebuffer.
+type SoftwareDisplay struct {
+	NullDisplay
+}
+
+// NewSoftwareDisplay creates a new software display
+func NewSoftwareDisplay(engine api.IEngine) *SoftwareDisplay {
+	o := new(SoftwareDisplay)
+	o.engine = engine
+	return o
+}
+
+// Initialize reports that rendering is done in software.
+func (s *SoftwareDisplay) Initialize(world api.IWorld) error {
+	fmt.Println("Initializing Software display...")
+	return nil
+}
+
+// Pre clears the rasterizer's image to the clear color.
+func (s *SoftwareDisplay) Pre() {
+	rasterizer := s.engine.World().Rasterizer()
+	if rasterizer != nil {
+		rasterizer.Clear(s.ClearColor())
+	}
+}
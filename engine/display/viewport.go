@@ -1,16 +1,30 @@
 // Package graphics provides visual
 package display
 
-import "github.com/go-gl/gl/v4.5-core/gl"
+import (
+	"math"
 
-// Viewport is a basic wrapper of an OpenGL viewport
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// Viewport is a basic wrapper of an OpenGL viewport. It also maps
+// a virtual resolution onto the device based on a scaling policy.
 type Viewport struct {
 	x, y, width, height int32
+
+	policy string
+
+	virtualWidth, virtualHeight int
+
+	// The projection's size in view units.
+	viewWidth, viewHeight float32
 }
 
 // NewViewport construct a viewport
 func NewViewport() *Viewport {
 	v := new(Viewport)
+	v.policy = api.ScaleNone
 	return v
 }
 
@@ -20,9 +34,90 @@ func (v *Viewport) SetDimensions(x, y, width, height int) {
 	v.y = int32(y)
 	v.width = int32(width)
 	v.height = int32(height)
+	v.viewWidth = float32(width)
+	v.viewHeight = float32(height)
+}
+
+// Configure sets the scaling policy and virtual resolution. Resize
+// must be called afterwards to compute the dimensions.
+func (v *Viewport) Configure(policy string, virtualWidth, virtualHeight int) {
+	if policy == "" {
+		policy = api.ScaleNone
+	}
+	v.policy = policy
+	v.virtualWidth = virtualWidth
+	v.virtualHeight = virtualHeight
+}
+
+// Policy returns the scaling policy
+func (v *Viewport) Policy() string {
+	return v.policy
+}
+
+// Resize recomputes the viewport for a device (framebuffer) size.
+func (v *Viewport) Resize(deviceWidth, deviceHeight int) {
+	// A minimized window reports a zero size.
+	if deviceWidth <= 0 || deviceHeight <= 0 {
+		return
+	}
+
+	dw := float64(deviceWidth)
+	dh := float64(deviceHeight)
+	vw := float64(v.virtualWidth)
+	vh := float64(v.virtualHeight)
+
+	if v.policy == api.ScaleNone || vw <= 0 || vh <= 0 {
+		v.SetDimensions(0, 0, deviceWidth, deviceHeight)
+		return
+	}
+
+	scale := math.Min(dw/vw, dh/vh)
+
+	switch v.policy {
+	case api.ScaleStretch:
+		v.SetDimensions(0, 0, deviceWidth, deviceHeight)
+		v.viewWidth, v.viewHeight = float32(vw), float32(vh)
+	case api.ScaleLetterbox, api.ScalePixelPerfect:
+		if v.policy == api.ScalePixelPerfect {
+			scale = math.Max(1.0, math.Floor(scale))
+		}
+		width := int(math.Round(vw * scale))
+		height := int(math.Round(vh * scale))
+		v.SetDimensions((deviceWidth-width)/2, (deviceHeight-height)/2, width, height)
+		v.viewWidth, v.viewHeight = float32(vw), float32(vh)
+	case api.ScaleExpand:
+		v.SetDimensions(0, 0, deviceWidth, deviceHeight)
+		v.viewWidth, v.viewHeight = float32(dw/scale), float32(dh/scale)
+	default:
+		v.SetDimensions(0, 0, deviceWidth, deviceHeight)
+	}
 }
 
 // Apply set the actual OpenGL viewport
 func (v *Viewport) Apply() {
 	gl.Viewport(v.x, v.y, v.width, v.height)
 }
+
+// Dimensions returns the viewport in device pixels
+func (v *Viewport) Dimensions() (x, y, width, height int) {
+	return int(v.x), int(v.y), int(v.width), int(v.height)
+}
+
+// ViewSize returns the projection's size in view units
+func (v *Viewport) ViewSize() (width, height float32) {
+	return v.viewWidth, v.viewHeight
+}
+
+// DeviceToProjection maps device coordinates into projection units.
+func (v *Viewport) DeviceToProjection(dvx, dvy float32) (x, y float32) {
+	x = (dvx - float32(v.x)) * v.viewWidth / float32(v.width)
+	y = (dvy - float32(v.y)) * v.viewHeight / float32(v.height)
+	return x, y
+}
+
+// ProjectionToDevice maps projection units into device coordinates.
+func (v *Viewport) ProjectionToDevice(x, y float32) (dvx, dvy float32) {
+	dvx = x*float32(v.width)/v.viewWidth + float32(v.x)
+	dvy = y*float32(v.height)/v.viewHeight + float32(v.y)
+	return dvx, dvy
+}
@@ -3,14 +3,19 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"math"
+	"os"
+	"path/filepath"
 	"runtime"
-	"time"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/configuration"
 	"github.com/wdevore/Ranger-Go-IGE/engine/display"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
 	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/atlas"
 	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/color"
+	"github.com/wdevore/Ranger-Go-IGE/engine/timing"
 	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
 )
 
@@ -31,45 +36,132 @@ type engine struct {
 	// -----------------------------------------
 	// Engine properties
 	// -----------------------------------------
-	running bool
+	running  bool
+	begun    bool
+	exitCode int
+
+	// -----------------------------------------
+	// Timing
+	// -----------------------------------------
+	clock api.IClock
+	ticks uint64
+
+	nsPerUpdate int64
+	msPerUpdate float64
+	frameScaler float64
 
 	// ---------------------------------------------------------------------
 	// Display
 	// ---------------------------------------------------------------------
-	windowDisplay *display.GlfwDisplay
+	windowDisplay api.IDisplay
 
 	projLoc int32
 	viewLoc int32
 
 	defaultBackgroundEnabled bool
 	backgroundAtlas          api.IAtlasX
+	background               *shapes.MonoSquareNode
+
+	// Kept so the config can be layered again when hot reloading.
+	overridesFS fs.FS
+	overrides   string
+	backend     string
 
 	// -----------------------------------------
 	// Debug
 	// -----------------------------------------
-	stepEnabled bool
-	infoNode    api.INode
+	infoNode api.INode
 }
 
-// Construct creates a new Engine
+// Construct creates a new Engine. The backend is taken from the
+// config file(s), which defaults to OpenGL.
 func Construct(relativePath string, overrides string) (eng api.IEngine, err error) {
+	return constructOnDisk(relativePath, overrides, "")
+}
+
+// ConstructHeadless creates a new Engine that uses the Headless backend
+// regardless of what the config file(s) specify. No window is opened
+// and no GL calls are made, which makes it suitable for tests and CI.
+func ConstructHeadless(relativePath string, overrides string) (eng api.IEngine, err error) {
+	return constructOnDisk(relativePath, overrides, api.BackendHeadless)
+}
+
+// ConstructSoftware creates a new Engine that uses the Software backend.
+// Scenes are rasterized into World().Rasterizer().Image() without a GPU.
+func ConstructSoftware(relativePath string, overrides string) (eng api.IEngine, err error) {
+	return constructOnDisk(relativePath, overrides, api.BackendSoftware)
+}
+
+// ConstructFS creates a new Engine that loads everything, including the
+// overrides config, from fsys. For example an embed.FS, a zip archive or
+// an in-memory FS. fsys is laid out like the engine's directory. A
+// backend, if not empty, overrides the config.
+func ConstructFS(fsys fs.FS, overrides string, backend string) (eng api.IEngine, err error) {
+	return construct(fsys, "", fsys, overrides, backend)
+}
+
+// constructOnDisk uses the directory at relativePath as the engine's
+// file system. The overrides are relative to the working directory.
+func constructOnDisk(relativePath string, overrides string, backend string) (eng api.IEngine, err error) {
+	dataPath, err := filepath.Abs(relativePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var overridesFS fs.FS
+	if overrides != "" {
+		file, err := filepath.Abs(overrides)
+		if err != nil {
+			return nil, err
+		}
+		overridesFS = os.DirFS(filepath.Dir(file))
+		overrides = filepath.Base(file)
+	}
+
+	return construct(os.DirFS(dataPath), relativePath, overridesFS, overrides, backend)
+}
+
+func construct(fsys fs.FS, relativePath string, overridesFS fs.FS, overrides string, backend string) (eng api.IEngine, err error) {
 	o := new(engine)
 
-	o.world = newWorld(relativePath)
+	o.clock = timing.NewWallClock()
 
-	if !o.world.Properties().Engine.Enabled {
+	o.world, err = newWorld(fsys, relativePath)
+	if err != nil {
+		return nil, err
+	}
+
+	props := o.world.Properties()
+
+	o.overridesFS = overridesFS
+	o.overrides = overrides
+	o.backend = backend
+
+	err = applyLayers(props, overridesFS, overrides, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	if !props.Engine.Enabled {
 		return nil, errors.New("Engine is NOT enabled in config file")
 	}
 
-	// Apply overrides
-	if overrides != "" {
-		o.world.PropertiesOverride(overrides)
+	err = props.Validate(fsys)
+	if err != nil {
+		return nil, err
 	}
 
 	// -----------------------------------------------------------
 	// Display and OpenGL
 	// -----------------------------------------------------------
-	o.windowDisplay = display.NewDisplay(o)
+	switch props.Engine.Backend {
+	case api.BackendHeadless:
+		o.windowDisplay = display.NewNullDisplay(o)
+	case api.BackendSoftware:
+		o.windowDisplay = display.NewSoftwareDisplay(o)
+	default:
+		o.windowDisplay = display.NewDisplay(o)
+	}
 
 	// Initializes GLFW and GL.
 	err = o.windowDisplay.Initialize(o.world)
@@ -114,9 +206,190 @@ func Construct(relativePath string, overrides string) (eng api.IEngine, err erro
 		return nil, err
 	}
 
+	o.watchConfig()
+
+	err = o.configureRecorder()
+	if err != nil {
+		return nil, err
+	}
+
 	return o, nil
 }
 
+// applyLayers applies, on top of the engine defaults, the game config,
+// environment variables and then command-line flags. A backend, if
+// given, overrides them all.
+func applyLayers(props *configuration.Properties, overridesFS fs.FS, overrides string, backend string) error {
+	if overrides != "" {
+		err := props.LoadFS(overridesFS, overrides)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := props.ApplyEnv(os.Environ())
+	if err != nil {
+		return err
+	}
+
+	err = props.ApplyFlags(os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	if backend != "" {
+		props.Engine.Backend = backend
+	}
+
+	return nil
+}
+
+// configureRecorder starts recording or playback as configured. Both
+// files are relative to the working directory.
+func (e *engine) configureRecorder() error {
+	ep := e.world.Properties().Engine
+	recorder := e.world.Recorder()
+
+	if ep.PlayInput != "" {
+		file, err := os.Open(ep.PlayInput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		err = recorder.Play(file)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Engine: playing input from ", ep.PlayInput)
+	}
+
+	if ep.RecordInput != "" {
+		recorder.Record()
+		fmt.Println("Engine: recording input to ", ep.RecordInput)
+	}
+
+	return nil
+}
+
+// saveRecording writes the recording, if any, when the engine ends.
+func (e *engine) saveRecording() {
+	ep := e.world.Properties().Engine
+	recorder := e.world.Recorder()
+
+	if ep.RecordInput == "" || !recorder.Recording() {
+		return
+	}
+
+	recorder.Stop()
+
+	file, err := os.Create(ep.RecordInput)
+	if err != nil {
+		fmt.Println("Engine: ", err)
+		return
+	}
+	defer file.Close()
+
+	err = recorder.Save(file)
+	if err != nil {
+		fmt.Println("Engine: ", err)
+		return
+	}
+	fmt.Println("Engine: input recorded to ", ep.RecordInput)
+}
+
+func (e *engine) watchConfig() {
+	reloader := e.world.HotReloader()
+
+	reloader.Watch(e.world.FS(), e.reloadConfig, api.ConfigFile)
+	if e.overrides != "" {
+		reloader.Watch(e.overridesFS, e.reloadConfig, e.overrides)
+	}
+}
+
+// reloadConfig layers the config again and applies the properties that
+// can change while running. Others, for example the window size,
+// require a restart.
+func (e *engine) reloadConfig() error {
+	props := &configuration.Properties{}
+
+	err := props.LoadFS(e.world.FS(), api.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	err = applyLayers(props, e.overridesFS, e.overrides, e.backend)
+	if err != nil {
+		return err
+	}
+
+	err = props.Validate(e.world.FS())
+	if err != nil {
+		return err
+	}
+
+	current := e.world.Properties()
+
+	wp := &current.Window
+	wp.ClearColor = props.Window.ClearColor
+	wp.BackgroundColor = props.Window.BackgroundColor
+
+	e.windowDisplay.SetClearColor(wp.ClearColor.R, wp.ClearColor.G, wp.ClearColor.B, wp.ClearColor.A)
+
+	if e.background != nil {
+		bgCol := wp.BackgroundColor
+		e.background.SetFilledColor(color.NewPaletteFromFloats(bgCol.R, bgCol.G, bgCol.B, bgCol.A))
+	}
+
+	ep := &current.Engine
+	ep.UPSRate = props.Engine.UPSRate
+	ep.FPSRate = props.Engine.FPSRate
+	e.configureTiming()
+
+	ep.GamepadDeadZone = props.Engine.GamepadDeadZone
+	e.world.Gamepads().SetDeadZone(ep.GamepadDeadZone)
+
+	fmt.Println("Engine: config reloaded")
+
+	return nil
+}
+
+// pollGamepads queues the events of any gamepad changes.
+func (e *engine) pollGamepads() {
+	queue := e.world.EventQueue()
+	e.world.Gamepads().Poll(func(event api.IEvent) {
+		event.SetTimestamp(e.clock.Now())
+		queue.Post(event)
+	})
+}
+
+// dispatchEvents routes the frame's queued events. This happens once
+// per frame, after polling and before the frame's updates, so handlers
+// never run in the middle of the display's polling.
+func (e *engine) dispatchEvents() {
+	e.world.EventQueue().Dispatch(e.world.RouteEvents)
+}
+
+// pollResources reloads any changed resources and uploads preloaded
+// assets. It is called between frames.
+func (e *engine) pollResources() {
+	reloader := e.world.HotReloader()
+	reloads := reloader.Reloads()
+
+	for _, err := range reloader.Poll() {
+		fmt.Println("Engine: reload failed:", err)
+	}
+
+	// A reloaded shader is relinked and left bound.
+	if reloader.Reloads() != reloads {
+		nodes.InvalidateAtlas()
+	}
+
+	for _, err := range e.world.Assets().Update() {
+		fmt.Println("Engine: asset failed:", err)
+	}
+}
+
 func (e *engine) configureBackgroundForgrounds() error {
 	// -----------------------------------------------------------
 	// Timing Info.
@@ -135,8 +408,8 @@ func (e *engine) configureBackgroundForgrounds() error {
 		gt.SetColor(color.NewPaletteInt64(color.Peach).Array())
 		gt.SetPixelSize(2.0)
 
-		dvr := e.world.Properties().Window.DeviceRes
-		e.infoNode.SetPosition(-float32(dvr.Width/2)+10.0, -float32(dvr.Height/2)+10.0)
+		viewWidth, viewHeight := e.world.Viewport().ViewSize()
+		e.infoNode.SetPosition(-viewWidth/2.0+10.0, -viewHeight/2.0+10.0)
 	}
 
 	// -----------------------------------------------------------
@@ -156,12 +429,11 @@ func (e *engine) configureBackgroundForgrounds() error {
 		if err != nil {
 			return err
 		}
-		dvr := worldProps.Window.DeviceRes
-		square.SetScaleComps(float32(dvr.Width), float32(dvr.Height))
+		square.SetScaleComps(e.world.Viewport().ViewSize())
 
-		sq := square.(*shapes.MonoSquareNode)
+		e.background = square.(*shapes.MonoSquareNode)
 		bgCol := worldProps.Window.BackgroundColor
-		sq.SetFilledColor(color.NewPaletteFromFloats(bgCol.R, bgCol.G, bgCol.B, bgCol.A))
+		e.background.SetFilledColor(color.NewPaletteFromFloats(bgCol.R, bgCol.G, bgCol.B, bgCol.A))
 
 		e.defaultBackgroundEnabled = true
 	case "Checkerboard":
@@ -173,6 +445,25 @@ func (e *engine) configureBackgroundForgrounds() error {
 // Begin is called after Construct() and as the last thing the engine
 // does to start the game.
 func (e *engine) Begin() error {
+	err := e.prepare()
+	if err != nil {
+		return err
+	}
+
+	// nodes.PrintTree(e.world.Root())
+	e.loop()
+
+	return nil
+}
+
+// prepare begins the scene graph and calculates the fixed update timing.
+// It is only performed once regardless of whether the game is
+// started by Begin() or Step().
+func (e *engine) prepare() error {
+	if e.begun {
+		return nil
+	}
+
 	e.running = true
 
 	sceneGraph := e.world.NodeManager()
@@ -196,55 +487,97 @@ func (e *engine) Begin() error {
 		}
 	}
 
-	// nodes.PrintTree(e.world.Root())
-	e.loop()
+	e.configureTiming()
+
+	e.begun = true
 
 	return nil
 }
 
+func (e *engine) configureTiming() {
+	engProps := e.world.Properties().Engine
+
+	updatePeriod := float64(second) / engProps.UPSRate
+	frameToUpdateRatio := engProps.FPSRate / engProps.UPSRate
+	e.frameScaler = frameToUpdateRatio / 1000000000.0
+
+	e.nsPerUpdate = int64(math.Round(updatePeriod))
+	e.msPerUpdate = float64(e.nsPerUpdate) / 1000000.0 // <-- milliseconds, Ex: 33.33333 or 16.6666666
+}
+
 func (e *engine) loop() {
 	display := e.windowDisplay
 	engProps := e.world.Properties().Engine
 
 	lag := int64(0)
-	updatePeriod := float64(second) / engProps.UPSRate
-	frameToUpdateRatio := engProps.FPSRate / engProps.UPSRate
-	frameScaler := frameToUpdateRatio / 1000000000.0
+	nsPerUpdate := e.nsPerUpdate
+	frameScaler := e.frameScaler
 
-	nsPerUpdate := int64(math.Round(updatePeriod))
-	msPerUpdate := float64(nsPerUpdate) / 1000000.0 // <-- milliseconds, Ex: 33.33333 or 16.6666666
 	upsCnt := 0
 	fpsCnt := 0
-	previousT := time.Now()
+	previousT := e.clock.Now()
 	secondCnt := int64(0)
 	renderElapsedTime := int64(0)
 	renderCnt := int64(0)
 	// avgRender := 0.0
 
 	sceneGraph := e.world.NodeManager()
+	debug := e.world.DebugController()
+	profiler := e.world.Profiler()
+
+	// Run limit tracking
+	frames := 0
+	startTicks := e.ticks
+	startT := previousT
 
 	for !display.Closed() && e.running {
-		currentT := time.Now()
+		currentT := e.clock.Now()
+
+		if e.runLimitReached(frames, e.ticks-startTicks, currentT-startT) {
+			fmt.Printf("Engine: run limit of %d %s reached. Exiting...\n", engProps.LoopFor, engProps.LoopUnit)
+			e.Exit(engProps.LoopExitCode)
+			continue
+		}
+
+		profiler.BeginFrame()
 
 		// ~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--
 		// Pump IO events
 		// ~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--
+		profiler.BeginPhase(api.ProfilePhasePoll)
 		display.Poll()
+		e.pollGamepads()
+		e.dispatchEvents()
+
+		e.pollResources()
+		// A reloaded config may change the update rate.
+		nsPerUpdate = e.nsPerUpdate
+		frameScaler = e.frameScaler
+		profiler.EndPhase(api.ProfilePhasePoll)
 
 		// ~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--
 		// Update
 		// ~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--
-		elapsedNano := (currentT.Sub(previousT)).Nanoseconds()
+		elapsedNano := currentT - previousT
 
 		// Note: This update is based on:
 		// https://gameprogrammingpatterns.com/game-loop.html
 
-		if !e.stepEnabled {
-			lag += elapsedNano
+		profiler.BeginPhase(api.ProfilePhaseUpdate)
+		if debug.Paused() {
+			// Only perform an update when a single step is requested.
+			if debug.TakeStep() {
+				e.update(sceneGraph, float64(nsPerUpdate)*frameScaler)
+				upsCnt++
+			}
+		} else {
+			// The time scale stretches or shrinks the elapsed time which
+			// means more or fewer fixed updates are performed.
+			lag += int64(float64(elapsedNano) * debug.TimeScale())
 			lagging := true
 			for lagging {
 				if lag >= nsPerUpdate {
-					sceneGraph.Update(msPerUpdate, float64(elapsedNano)*frameScaler)
+					e.update(sceneGraph, float64(elapsedNano)*frameScaler)
 					lag -= nsPerUpdate
 					upsCnt++
 				} else {
@@ -252,6 +585,7 @@ func (e *engine) loop() {
 				}
 			}
 		}
+		profiler.EndPhase(api.ProfilePhaseUpdate)
 
 		// ~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--~--
 		// Render Scenegraph by visiting the nodes
@@ -259,16 +593,19 @@ func (e *engine) loop() {
 		display.Pre() // Things like background clearing
 		// **** Any rendering and timing must occur AFTER this point ****
 
-		renderT := time.Now()
+		renderT := e.clock.Now()
 
 		// Calc interpolation for nodes that need it.
 		interpolation := float64(lag) / float64(nsPerUpdate)
 
 		// Once the last scene has exited the stage we stop running.
+		profiler.BeginPhase(api.ProfilePhaseVisit)
 		moreScenes := sceneGraph.Visit(interpolation)
+		profiler.EndPhase(api.ProfilePhaseVisit)
 
 		if !moreScenes {
 			fmt.Println("Engine: no more nodes to visit. Exiting...")
+			profiler.EndFrame()
 			e.running = false
 			continue
 		}
@@ -282,7 +619,7 @@ func (e *engine) loop() {
 			renderCnt = 0
 			renderElapsedTime = 0
 		} else {
-			renderElapsedTime += (time.Now().Sub(renderT)).Microseconds()
+			renderElapsedTime += (e.clock.Now() - renderT) / 1000
 			renderCnt++
 		}
 
@@ -304,16 +641,136 @@ func (e *engine) loop() {
 		fpsCnt++
 		previousT = currentT
 
+		profiler.BeginPhase(api.ProfilePhaseSwap)
 		display.Swap()
+		profiler.EndPhase(api.ProfilePhaseSwap)
+
+		profiler.EndFrame()
+
+		frames++
+	}
+}
+
+// runLimitReached checks Engine.LoopFor. A negative LoopFor runs forever.
+func (e *engine) runLimitReached(frames int, updates uint64, elapsedNano int64) bool {
+	engProps := e.world.Properties().Engine
+
+	limit := engProps.LoopFor
+	if limit < 0 {
+		return false
+	}
+
+	switch engProps.LoopUnit {
+	case api.LoopUpdates:
+		return updates >= uint64(limit)
+	case api.LoopSeconds:
+		return elapsedNano >= int64(limit)*second
+	default:
+		return frames >= limit
+	}
+}
+
+func (e *engine) update(sceneGraph api.INodeManager, secPerUpdate float64) {
+	e.world.Recorder().Advance(e.ticks, e.world.RouteEvents)
+	e.world.Input().Update()
+	e.world.InputState().Update()
+	sceneGraph.Update(e.msPerUpdate, secPerUpdate)
+	e.ticks++
+}
+
+// Step performs a fixed number of updates independent of the clock.
+// If the clock is a manual clock it is advanced by the same amount
+// of simulated time.
+func (e *engine) Step(updates int, visit bool) (bool, error) {
+	err := e.prepare()
+	if err != nil {
+		return false, err
+	}
+
+	sceneGraph := e.world.NodeManager()
+	profiler := e.world.Profiler()
+
+	profiler.BeginFrame()
+	defer profiler.EndFrame()
+
+	profiler.BeginPhase(api.ProfilePhasePoll)
+	e.pollGamepads()
+	e.dispatchEvents()
+	e.pollResources()
+	profiler.EndPhase(api.ProfilePhasePoll)
+
+	profiler.BeginPhase(api.ProfilePhaseUpdate)
+	for i := 0; i < updates; i++ {
+		e.update(sceneGraph, float64(e.nsPerUpdate)*e.frameScaler)
+	}
+	profiler.EndPhase(api.ProfilePhaseUpdate)
+
+	if mc, ok := e.clock.(api.IManualClock); ok {
+		mc.Advance(int64(updates) * e.nsPerUpdate)
 	}
+
+	if !visit {
+		return true, nil
+	}
+
+	e.windowDisplay.Pre()
+
+	profiler.BeginPhase(api.ProfilePhaseVisit)
+	moreScenes := sceneGraph.Visit(0.0)
+	profiler.EndPhase(api.ProfilePhaseVisit)
+
+	profiler.BeginPhase(api.ProfilePhaseSwap)
+	e.windowDisplay.Swap()
+	profiler.EndPhase(api.ProfilePhaseSwap)
+
+	return moreScenes, nil
+}
+
+func (e *engine) Ticks() uint64 {
+	return e.ticks
+}
+
+func (e *engine) SetClock(clock api.IClock) {
+	e.clock = clock
+}
+
+func (e *engine) Clock() api.IClock {
+	return e.clock
+}
+
+func (e *engine) DebugController() api.IDebugController {
+	return e.world.DebugController()
+}
+
+func (e *engine) Profiler() api.IProfiler {
+	return e.world.Profiler()
+}
+
+func (e *engine) HotReloader() api.IHotReloader {
+	return e.world.HotReloader()
 }
 
 func (e *engine) End() {
 	fmt.Println("Engine shutting down...")
+	e.saveRecording()
+
 	// Oh noooo! The world is coming to an end!
 	e.world.End()
 
 	e.windowDisplay.Shutdown()
+
+	if e.exitCode != 0 {
+		fmt.Println("Engine: exit code ", e.exitCode)
+	}
+}
+
+func (e *engine) Exit(code int) {
+	e.exitCode = code
+	e.running = false
+}
+
+func (e *engine) ExitCode() int {
+	return e.exitCode
 }
 
 func (e *engine) World() api.IWorld {
@@ -0,0 +1,210 @@
+package input
+
+import (
+	"math"
+	"sort"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
+)
+
+type gamepad struct {
+	name    string
+	buttons [api.GamepadButtonCount]bool
+	axes    [api.GamepadAxisCount]float32
+}
+
+type gamepads struct {
+	source   api.IGamepadSource
+	deadZone float32
+
+	pads map[int]*gamepad
+
+	event *io.Event
+
+	// Scratch state filled by the source
+	buttons []bool
+	axes    []float32
+}
+
+// NewGamepads creates a poller without a source. Nothing is routed
+// until a source is set.
+func NewGamepads(deadZone float32) api.IGamepads {
+	o := new(gamepads)
+	o.deadZone = deadZone
+	o.pads = make(map[int]*gamepad)
+	o.event = io.NewEvent()
+	o.buttons = make([]bool, api.GamepadButtonCount)
+	o.axes = make([]float32, api.GamepadAxisCount)
+	return o
+}
+
+func (g *gamepads) SetSource(source api.IGamepadSource) {
+	g.source = source
+}
+
+func (g *gamepads) Source() api.IGamepadSource {
+	return g.source
+}
+
+func (g *gamepads) SetDeadZone(zone float32) {
+	g.deadZone = zone
+}
+
+func (g *gamepads) Poll(route func(event api.IEvent)) {
+	if g.source == nil {
+		return
+	}
+
+	ids := g.source.Gamepads()
+	sort.Ints(ids)
+
+	connected := make(map[int]bool)
+
+	for _, id := range ids {
+		connected[id] = true
+
+		pad, ok := g.pads[id]
+		if !ok {
+			pad = &gamepad{name: g.source.Name(id)}
+			g.pads[id] = pad
+			route(g.newEvent(api.IOTypeGamepadConnected, id))
+		}
+
+		if !g.source.State(id, g.buttons, g.axes) {
+			continue
+		}
+
+		for button, down := range g.buttons {
+			if pad.buttons[button] != down {
+				pad.buttons[button] = down
+				g.routeButton(route, id, button, down)
+			}
+		}
+
+		axes := g.applyDeadZone(g.axes)
+		for axis, value := range axes {
+			if pad.axes[axis] != value {
+				pad.axes[axis] = value
+				event := g.newEvent(api.IOTypeGamepadAxis, id)
+				event.SetAxis(uint8(axis), value)
+				route(event)
+			}
+		}
+	}
+
+	disconnected := []int{}
+	for id := range g.pads {
+		if !connected[id] {
+			disconnected = append(disconnected, id)
+		}
+	}
+	sort.Ints(disconnected)
+
+	for _, id := range disconnected {
+		// Release held buttons so nothing is left stuck down.
+		pad := g.pads[id]
+		for button, down := range pad.buttons {
+			if down {
+				g.routeButton(route, id, button, false)
+			}
+		}
+
+		delete(g.pads, id)
+		route(g.newEvent(api.IOTypeGamepadDisconnected, id))
+	}
+}
+
+func (g *gamepads) newEvent(eventType uint32, id int) *io.Event {
+	g.event.Reset()
+	g.event.SetType(eventType)
+	g.event.SetWhich(uint32(id))
+	return g.event
+}
+
+func (g *gamepads) routeButton(route func(event api.IEvent), id int, button int, down bool) {
+	eventType := uint32(api.IOTypeGamepadButtonUp)
+	if down {
+		eventType = api.IOTypeGamepadButtonDown
+	}
+	event := g.newEvent(eventType, id)
+	event.SetButton(uint8(button))
+	route(event)
+}
+
+// applyDeadZone uses a radial dead-zone for each stick so diagonals
+// aren't clipped. Triggers are mapped from [-1, 1] to [0, 1] first.
+func (g *gamepads) applyDeadZone(raw []float32) [api.GamepadAxisCount]float32 {
+	var axes [api.GamepadAxisCount]float32
+
+	axes[api.GamepadAxisLeftX], axes[api.GamepadAxisLeftY] =
+		g.stick(raw[api.GamepadAxisLeftX], raw[api.GamepadAxisLeftY])
+	axes[api.GamepadAxisRightX], axes[api.GamepadAxisRightY] =
+		g.stick(raw[api.GamepadAxisRightX], raw[api.GamepadAxisRightY])
+
+	axes[api.GamepadAxisLeftTrigger] = g.trigger(raw[api.GamepadAxisLeftTrigger])
+	axes[api.GamepadAxisRightTrigger] = g.trigger(raw[api.GamepadAxisRightTrigger])
+
+	return axes
+}
+
+func (g *gamepads) stick(x, y float32) (float32, float32) {
+	magnitude := float32(math.Hypot(float64(x), float64(y)))
+	if magnitude <= g.deadZone {
+		return 0.0, 0.0
+	}
+
+	scaled := (magnitude - g.deadZone) / (1.0 - g.deadZone)
+	if scaled > 1.0 {
+		scaled = 1.0
+	}
+
+	return x / magnitude * scaled, y / magnitude * scaled
+}
+
+func (g *gamepads) trigger(value float32) float32 {
+	value = (value + 1.0) / 2.0
+	if value <= g.deadZone {
+		return 0.0
+	}
+
+	value = (value - g.deadZone) / (1.0 - g.deadZone)
+	if value > 1.0 {
+		value = 1.0
+	}
+
+	return value
+}
+
+func (g *gamepads) Connected() []int {
+	ids := make([]int, 0, len(g.pads))
+	for id := range g.pads {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (g *gamepads) Name(id int) string {
+	pad, ok := g.pads[id]
+	if !ok {
+		return ""
+	}
+	return pad.name
+}
+
+func (g *gamepads) Button(id int, button int) bool {
+	pad, ok := g.pads[id]
+	if !ok || button < 0 || button >= api.GamepadButtonCount {
+		return false
+	}
+	return pad.buttons[button]
+}
+
+func (g *gamepads) Axis(id int, axis int) float32 {
+	pad, ok := g.pads[id]
+	if !ok || axis < 0 || axis >= api.GamepadAxisCount {
+		return 0.0
+	}
+	return pad.axes[axis]
+}
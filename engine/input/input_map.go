@@ -0,0 +1,363 @@
+// Package input maps raw keyboard and mouse events to named actions.
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+const (
+	deviceKey = iota
+	deviceMouse
+	deviceModifier
+)
+
+type binding struct {
+	device int
+	code   int
+	mods   int
+}
+
+type action struct {
+	bindings []binding
+
+	held     bool
+	pressed  bool
+	released bool
+
+	// Presses seen since the last Update. This catches a tap that is
+	// pressed and released between two ticks.
+	presses int
+}
+
+type axis struct {
+	negative string
+	positive string
+}
+
+type inputMap struct {
+	actions map[string]*action
+	axes    map[string]*axis
+
+	// Raw state
+	keys    map[int]bool
+	buttons map[int]bool
+}
+
+// NewInputMap creates an input map without any bindings.
+func NewInputMap() api.IInputMap {
+	o := new(inputMap)
+	o.actions = make(map[string]*action)
+	o.axes = make(map[string]*axis)
+	o.keys = make(map[int]bool)
+	o.buttons = make(map[int]bool)
+	return o
+}
+
+func (m *inputMap) action(name string) *action {
+	a, ok := m.actions[name]
+	if !ok {
+		a = &action{}
+		m.actions[name] = a
+	}
+	return a
+}
+
+func (m *inputMap) BindKey(action string, key int, mods int) {
+	a := m.action(action)
+	a.bindings = append(a.bindings, binding{device: deviceKey, code: key, mods: mods})
+}
+
+func (m *inputMap) BindMouseButton(action string, button int, mods int) {
+	a := m.action(action)
+	a.bindings = append(a.bindings, binding{device: deviceMouse, code: button, mods: mods})
+}
+
+func (m *inputMap) BindModifier(action string, mod int) {
+	a := m.action(action)
+	a.bindings = append(a.bindings, binding{device: deviceModifier, code: mod})
+}
+
+func (m *inputMap) Unbind(action string) {
+	a, ok := m.actions[action]
+	if ok {
+		a.bindings = nil
+	}
+}
+
+func (m *inputMap) BindAxis(name string, negative, positive string) {
+	m.axes[name] = &axis{negative: negative, positive: positive}
+}
+
+func (m *inputMap) Actions() []string {
+	names := make([]string, 0, len(m.actions))
+	for name := range m.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mods derives the modifier bits from the modifier keys held.
+func (m *inputMap) mods() int {
+	mods := 0
+	for mod, keys := range modKeys {
+		if m.keys[keys[0]] || m.keys[keys[1]] {
+			mods |= mod
+		}
+	}
+	return mods
+}
+
+func (m *inputMap) Handle(event api.IEvent) {
+	switch event.GetType() {
+	case api.IOTypeKeyboard:
+		code := int(event.GetKeyCode())
+		switch event.GetState() {
+		case api.KeyPress:
+			m.keys[code] = true
+			m.tap(deviceKey, code)
+		case api.KeyRelease:
+			delete(m.keys, code)
+		}
+	case api.IOTypeMouseButtonDown:
+		code := int(event.GetButton())
+		m.buttons[code] = true
+		m.tap(deviceMouse, code)
+	case api.IOTypeMouseButtonUp:
+		delete(m.buttons, int(event.GetButton()))
+	}
+}
+
+// tap counts a press against every action with a binding that the
+// press just activated.
+func (m *inputMap) tap(device int, code int) {
+	for _, a := range m.actions {
+		for _, b := range a.bindings {
+			if m.activatedBy(b, device, code) {
+				a.presses++
+				break
+			}
+		}
+	}
+}
+
+func (m *inputMap) activatedBy(b binding, device int, code int) bool {
+	if b.device == deviceModifier {
+		if device != deviceKey {
+			return false
+		}
+		keys := modKeys[b.code]
+		return code == keys[0] || code == keys[1]
+	}
+
+	return b.device == device && b.code == code && m.mods()&b.mods == b.mods
+}
+
+func (m *inputMap) bindingHeld(b binding) bool {
+	mods := m.mods()
+
+	switch b.device {
+	case deviceKey:
+		return m.keys[b.code] && mods&b.mods == b.mods
+	case deviceMouse:
+		return m.buttons[b.code] && mods&b.mods == b.mods
+	default:
+		return mods&b.code != 0
+	}
+}
+
+func (m *inputMap) Update() {
+	for _, a := range m.actions {
+		held := false
+		for _, b := range a.bindings {
+			if m.bindingHeld(b) {
+				held = true
+				break
+			}
+		}
+
+		tapped := a.presses > 0
+		a.pressed = !a.held && (held || tapped)
+		a.released = (a.held || tapped) && !held
+		a.held = held
+		a.presses = 0
+	}
+}
+
+func (m *inputMap) Pressed(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.pressed
+}
+
+func (m *inputMap) Held(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.held
+}
+
+func (m *inputMap) Released(action string) bool {
+	a, ok := m.actions[action]
+	return ok && a.released
+}
+
+func (m *inputMap) Axis(name string) float64 {
+	ax, ok := m.axes[name]
+	if !ok {
+		return 0.0
+	}
+
+	value := 0.0
+	if m.Held(ax.negative) {
+		value -= 1.0
+	}
+	if m.Held(ax.positive) {
+		value += 1.0
+	}
+	return value
+}
+
+// --------------------------------------------------------------------------
+// JSON
+// --------------------------------------------------------------------------
+
+// Example:
+//
+//	{
+//	  "Actions": {
+//	    "thrust": [{"Key": "Z"}],
+//	    "fire": [{"Mouse": "Left"}, {"Key": "Space", "Mods": ["Shift"]}],
+//	    "boost": [{"Modifier": "Control"}]
+//	  },
+//	  "Axes": {
+//	    "turn": {"Negative": "left", "Positive": "right"}
+//	  }
+//	}
+type bindingJSON struct {
+	Key      string   `json:",omitempty"`
+	Mouse    string   `json:",omitempty"`
+	Modifier string   `json:",omitempty"`
+	Mods     []string `json:",omitempty"`
+}
+
+type axisJSON struct {
+	Negative string
+	Positive string
+}
+
+type inputMapJSON struct {
+	Actions map[string][]bindingJSON
+	Axes    map[string]axisJSON `json:",omitempty"`
+}
+
+func (m *inputMap) Load(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	data := inputMapJSON{}
+	err := decoder.Decode(&data)
+	if err != nil {
+		return fmt.Errorf("InputMap: %v", err)
+	}
+
+	// Nothing is replaced until everything has been parsed.
+	actions := make(map[string]*action)
+	for name, bindings := range data.Actions {
+		a := &action{}
+		for _, bj := range bindings {
+			b, err := bj.binding()
+			if err != nil {
+				return fmt.Errorf("InputMap: action '%s': %v", name, err)
+			}
+			a.bindings = append(a.bindings, b)
+		}
+		actions[name] = a
+	}
+
+	axes := make(map[string]*axis)
+	for name, aj := range data.Axes {
+		axes[name] = &axis{negative: aj.Negative, positive: aj.Positive}
+	}
+
+	m.actions = actions
+	m.axes = axes
+
+	return nil
+}
+
+func (m *inputMap) Save(w io.Writer) error {
+	data := inputMapJSON{
+		Actions: make(map[string][]bindingJSON),
+		Axes:    make(map[string]axisJSON),
+	}
+
+	for name, a := range m.actions {
+		bindings := []bindingJSON{}
+		for _, b := range a.bindings {
+			bindings = append(bindings, b.json())
+		}
+		data.Actions[name] = bindings
+	}
+
+	for name, ax := range m.axes {
+		data.Axes[name] = axisJSON{Negative: ax.negative, Positive: ax.positive}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&data)
+}
+
+func (bj bindingJSON) binding() (binding, error) {
+	b := binding{}
+
+	for _, name := range bj.Mods {
+		mod, err := lookup(modNames, name, "modifier")
+		if err != nil {
+			return b, err
+		}
+		b.mods |= mod
+	}
+
+	var err error
+
+	switch {
+	case bj.Key != "" && bj.Mouse == "" && bj.Modifier == "":
+		b.device = deviceKey
+		b.code, err = lookup(keyNames, bj.Key, "key")
+	case bj.Mouse != "" && bj.Key == "" && bj.Modifier == "":
+		b.device = deviceMouse
+		b.code, err = lookup(mouseNames, bj.Mouse, "mouse button")
+	case bj.Modifier != "" && bj.Key == "" && bj.Mouse == "":
+		b.device = deviceModifier
+		b.code, err = lookup(modNames, bj.Modifier, "modifier")
+	default:
+		err = fmt.Errorf("a binding needs exactly one of Key, Mouse or Modifier")
+	}
+
+	return b, err
+}
+
+func (b binding) json() bindingJSON {
+	bj := bindingJSON{}
+
+	switch b.device {
+	case deviceKey:
+		bj.Key = reverse(keyNames, b.code)
+	case deviceMouse:
+		bj.Mouse = reverse(mouseNames, b.code)
+	default:
+		bj.Modifier = reverse(modNames, b.code)
+	}
+
+	for _, mod := range []int{api.ModShift, api.ModControl, api.ModAlt, api.ModSuper} {
+		if b.mods&mod != 0 {
+			bj.Mods = append(bj.Mods, reverse(modNames, mod))
+		}
+	}
+
+	return bj
+}
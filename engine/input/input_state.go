@@ -0,0 +1,146 @@
+package input
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+)
+
+// snapshot is the state for a single tick.
+type snapshot struct {
+	keys    map[int]bool
+	buttons map[int]bool
+
+	x, y int32
+	view api.IPoint
+}
+
+func newSnapshot() *snapshot {
+	o := new(snapshot)
+	o.keys = make(map[int]bool)
+	o.buttons = make(map[int]bool)
+	o.view = geometry.NewPoint()
+	return o
+}
+
+type inputState struct {
+	world api.IWorld
+
+	current  *snapshot
+	previous *snapshot
+
+	// Raw state, changed by events between ticks
+	keys    map[int]bool
+	buttons map[int]bool
+	x, y    int32
+
+	// Keys and buttons pressed since the last tick. They are down for
+	// the next tick even if released again before it.
+	pressedKeys    map[int]bool
+	pressedButtons map[int]bool
+}
+
+// NewInputState creates a state where nothing is down. The world maps
+// the cursor to view-space.
+func NewInputState(world api.IWorld) api.IInputState {
+	o := new(inputState)
+	o.world = world
+	o.current = newSnapshot()
+	o.previous = newSnapshot()
+	o.keys = make(map[int]bool)
+	o.buttons = make(map[int]bool)
+	o.pressedKeys = make(map[int]bool)
+	o.pressedButtons = make(map[int]bool)
+	return o
+}
+
+func (s *inputState) Handle(event api.IEvent) {
+	switch event.GetType() {
+	case api.IOTypeKeyboard:
+		code := int(event.GetKeyCode())
+		switch event.GetState() {
+		case api.KeyPress:
+			s.keys[code] = true
+			s.pressedKeys[code] = true
+		case api.KeyRelease:
+			delete(s.keys, code)
+		}
+	case api.IOTypeMouseButtonDown:
+		code := int(event.GetButton())
+		s.buttons[code] = true
+		s.pressedButtons[code] = true
+		s.x, s.y = event.GetMousePosition()
+	case api.IOTypeMouseButtonUp:
+		delete(s.buttons, int(event.GetButton()))
+		s.x, s.y = event.GetMousePosition()
+	case api.IOTypeMouseMotion:
+		s.x, s.y = event.GetMousePosition()
+	}
+}
+
+func (s *inputState) Update() {
+	// Reuse the oldest snapshot for the new one.
+	s.previous, s.current = s.current, s.previous
+	cur := s.current
+
+	copyInto(cur.keys, s.keys, s.pressedKeys)
+	copyInto(cur.buttons, s.buttons, s.pressedButtons)
+
+	cur.x, cur.y = s.x, s.y
+	nodes.MapDeviceToView(s.world, s.x, s.y, cur.view)
+}
+
+// copyInto sets dst to the union of down and pressed, then clears
+// pressed.
+func copyInto(dst, down, pressed map[int]bool) {
+	for code := range dst {
+		delete(dst, code)
+	}
+	for code := range down {
+		dst[code] = true
+	}
+	for code := range pressed {
+		dst[code] = true
+		delete(pressed, code)
+	}
+}
+
+func (s *inputState) IsDown(key int) bool {
+	return s.current.keys[key]
+}
+
+func (s *inputState) WentDown(key int) bool {
+	return s.current.keys[key] && !s.previous.keys[key]
+}
+
+func (s *inputState) WentUp(key int) bool {
+	return !s.current.keys[key] && s.previous.keys[key]
+}
+
+func (s *inputState) IsButtonDown(button int) bool {
+	return s.current.buttons[button]
+}
+
+func (s *inputState) ButtonWentDown(button int) bool {
+	return s.current.buttons[button] && !s.previous.buttons[button]
+}
+
+func (s *inputState) ButtonWentUp(button int) bool {
+	return !s.current.buttons[button] && s.previous.buttons[button]
+}
+
+func (s *inputState) Cursor() (x, y int32) {
+	return s.current.x, s.current.y
+}
+
+func (s *inputState) PreviousCursor() (x, y int32) {
+	return s.previous.x, s.previous.y
+}
+
+func (s *inputState) CursorView() api.IPoint {
+	return s.current.view
+}
+
+func (s *inputState) PreviousCursorView() api.IPoint {
+	return s.previous.view
+}
@@ -0,0 +1,90 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// Key codes are GLFW's. Only those named here can be written by name,
+// any other is written as its number.
+var keyNames = map[string]int{
+	"Space": 32, "'": 39, ",": 44, "-": 45, ".": 46, "/": 47,
+	";": 59, "=": 61, "[": 91, "\\": 92, "]": 93, "`": 96,
+
+	"Escape": 256, "Enter": 257, "Tab": 258, "Backspace": 259,
+	"Insert": 260, "Delete": 261,
+	"Right": 262, "Left": 263, "Down": 264, "Up": 265,
+	"PageUp": 266, "PageDown": 267, "Home": 268, "End": 269,
+
+	"LeftShift": 340, "LeftControl": 341, "LeftAlt": 342, "LeftSuper": 343,
+	"RightShift": 344, "RightControl": 345, "RightAlt": 346, "RightSuper": 347,
+}
+
+var mouseNames = map[string]int{
+	"Left":   api.MouseButtonLeft,
+	"Right":  api.MouseButtonRight,
+	"Middle": api.MouseButtonMiddle,
+}
+
+var modNames = map[string]int{
+	"Shift":   api.ModShift,
+	"Control": api.ModControl,
+	"Alt":     api.ModAlt,
+	"Super":   api.ModSuper,
+}
+
+// modKeys are the left and right keys of each modifier.
+var modKeys = map[int][2]int{
+	api.ModShift:   {340, 344},
+	api.ModControl: {341, 345},
+	api.ModAlt:     {342, 346},
+	api.ModSuper:   {343, 347},
+}
+
+func init() {
+	for c := '0'; c <= '9'; c++ {
+		keyNames[string(c)] = int(c)
+	}
+	for c := 'A'; c <= 'Z'; c++ {
+		keyNames[string(c)] = int(c)
+	}
+	for f := 1; f <= 12; f++ {
+		keyNames[fmt.Sprintf("F%d", f)] = 289 + f
+	}
+}
+
+// KeyCode returns the GLFW key code for a key name, for example "A",
+// "Space" or "F1". A number is taken as a key code.
+func KeyCode(name string) (int, error) {
+	return lookup(keyNames, name, "key")
+}
+
+// KeyName is the inverse of KeyCode.
+func KeyName(code int) string {
+	return reverse(keyNames, code)
+}
+
+func lookup(names map[string]int, name string, kind string) (int, error) {
+	code, ok := names[name]
+	if ok {
+		return code, nil
+	}
+
+	code, err := strconv.Atoi(name)
+	if err != nil {
+		return 0, fmt.Errorf("InputMap: unknown %s '%s'", kind, name)
+	}
+
+	return code, nil
+}
+
+func reverse(names map[string]int, code int) string {
+	for name, c := range names {
+		if c == code {
+			return name
+		}
+	}
+	return strconv.Itoa(code)
+}
@@ -0,0 +1,78 @@
+package input
+
+import (
+	"sort"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+type mockGamepad struct {
+	name    string
+	buttons [api.GamepadButtonCount]bool
+	axes    [api.GamepadAxisCount]float32
+}
+
+type mockGamepadSource struct {
+	pads map[int]*mockGamepad
+}
+
+// NewMockGamepadSource creates a source without any gamepads.
+func NewMockGamepadSource() api.IMockGamepadSource {
+	o := new(mockGamepadSource)
+	o.pads = make(map[int]*mockGamepad)
+	return o
+}
+
+// Connect adds a gamepad at rest, i.e. sticks centered and triggers
+// released.
+func (m *mockGamepadSource) Connect(id int, name string) {
+	pad := &mockGamepad{name: name}
+	pad.axes[api.GamepadAxisLeftTrigger] = -1.0
+	pad.axes[api.GamepadAxisRightTrigger] = -1.0
+	m.pads[id] = pad
+}
+
+func (m *mockGamepadSource) Disconnect(id int) {
+	delete(m.pads, id)
+}
+
+func (m *mockGamepadSource) SetButton(id int, button int, down bool) {
+	pad, ok := m.pads[id]
+	if ok && button >= 0 && button < api.GamepadButtonCount {
+		pad.buttons[button] = down
+	}
+}
+
+func (m *mockGamepadSource) SetAxis(id int, axis int, value float32) {
+	pad, ok := m.pads[id]
+	if ok && axis >= 0 && axis < api.GamepadAxisCount {
+		pad.axes[axis] = value
+	}
+}
+
+func (m *mockGamepadSource) Gamepads() []int {
+	ids := make([]int, 0, len(m.pads))
+	for id := range m.pads {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (m *mockGamepadSource) Name(id int) string {
+	pad, ok := m.pads[id]
+	if !ok {
+		return ""
+	}
+	return pad.name
+}
+
+func (m *mockGamepadSource) State(id int, buttons []bool, axes []float32) bool {
+	pad, ok := m.pads[id]
+	if !ok {
+		return false
+	}
+	copy(buttons, pad.buttons[:])
+	copy(axes, pad.axes[:])
+	return true
+}
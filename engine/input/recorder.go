@@ -0,0 +1,298 @@
+package input
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	eio "github.com/wdevore/Ranger-Go-IGE/engine/io"
+)
+
+// recordingMagic begins every recording. The last byte is the version.
+var recordingMagic = []byte{'R', 'N', 'G', 'I', 2}
+
+// recordedEvent is a copy of an event's fields. Events are reused by
+// their sources so they can't be kept.
+type recordedEvent struct {
+	tick uint64
+
+	eType     uint32
+	which     uint32
+	state     uint32
+	dir       uint32
+	keyScan   uint32
+	keyCode   uint32
+	keyMotif  uint32
+	clicks    uint8
+	button    uint8
+	repeat    uint8
+	axis      uint8
+	axisValue float32
+	scrollX   float64
+	scrollY   float64
+	char      rune
+
+	mx, my       int32
+	mxRel, myRel int32
+}
+
+type inputRecorder struct {
+	recording bool
+	playing   bool
+
+	// The tick of the next update
+	next uint64
+	// The tick recording or playback began at
+	start uint64
+
+	events []recordedEvent
+	// Ticks covered by the recording
+	length uint64
+
+	// Index of the next event to play
+	played int
+	event  *eio.Event
+}
+
+// NewInputRecorder creates a recorder that is neither recording nor
+// playing.
+func NewInputRecorder() api.IInputRecorder {
+	o := new(inputRecorder)
+	o.event = eio.NewEvent()
+	return o
+}
+
+func (r *inputRecorder) Record() {
+	r.playing = false
+	r.recording = true
+	r.start = r.next
+	r.events = nil
+	r.length = 0
+}
+
+func (r *inputRecorder) Recording() bool {
+	return r.recording
+}
+
+func (r *inputRecorder) Play(reader io.Reader) error {
+	events, length, err := decodeRecording(reader)
+	if err != nil {
+		return err
+	}
+
+	r.recording = false
+	r.playing = true
+	r.start = r.next
+	r.events = events
+	r.length = length
+	r.played = 0
+
+	return nil
+}
+
+func (r *inputRecorder) Playing() bool {
+	return r.playing
+}
+
+func (r *inputRecorder) Stop() {
+	if r.recording {
+		r.length = r.next - r.start
+	}
+	r.recording = false
+	r.playing = false
+}
+
+func (r *inputRecorder) Capture(event api.IEvent) {
+	if !r.recording {
+		return
+	}
+
+	re := recordedEvent{tick: r.next - r.start}
+	re.eType = event.GetType()
+	re.which = event.GetWhich()
+	re.state = event.GetState()
+	re.dir = event.GetDirection()
+	re.keyScan = event.GetKeyScan()
+	re.keyCode = event.GetKeyCode()
+	re.keyMotif = event.GetKeyMotif()
+	re.clicks = event.GetClicks()
+	re.button = event.GetButton()
+	re.repeat = event.GetRepeat()
+	re.axis, re.axisValue = event.GetAxis()
+	re.scrollX, re.scrollY = event.GetScroll()
+	re.char = event.GetRune()
+	re.mx, re.my = event.GetMousePosition()
+	re.mxRel, re.myRel = event.GetMouseRelMovement()
+
+	r.events = append(r.events, re)
+}
+
+func (r *inputRecorder) Advance(tick uint64, route func(event api.IEvent)) {
+	r.next = tick
+
+	if r.playing {
+		relative := tick - r.start
+		if relative >= r.length {
+			r.playing = false
+		} else {
+			for r.played < len(r.events) && r.events[r.played].tick == relative {
+				route(r.toEvent(&r.events[r.played]))
+				r.played++
+			}
+		}
+	}
+
+	r.next = tick + 1
+}
+
+func (r *inputRecorder) toEvent(re *recordedEvent) api.IEvent {
+	e := r.event
+	e.Reset()
+	e.SetType(re.eType)
+	e.SetWhich(re.which)
+	e.SetState(re.state)
+	e.SetDirection(re.dir)
+	e.SetKeyScan(re.keyScan)
+	e.SetKeyCode(re.keyCode)
+	e.SetKeyMotif(re.keyMotif)
+	e.SetClicks(re.clicks)
+	e.SetButton(re.button)
+	e.SetRepeat(re.repeat)
+	e.SetAxis(re.axis, re.axisValue)
+	e.SetScroll(re.scrollX, re.scrollY)
+	e.SetRune(re.char)
+	e.SetMousePosition(re.mx, re.my)
+	e.SetMouseRelMovement(re.mxRel, re.myRel)
+	return e
+}
+
+// --------------------------------------------------------------------------
+// Encoding
+// --------------------------------------------------------------------------
+
+// The recording is the magic, then the length in ticks and the event
+// count, followed by the events. Each event's tick is stored as the
+// delta from the previous event's. All values are varints, most of
+// which are zero and thus a single byte.
+
+func (r *inputRecorder) Save(w io.Writer) error {
+	length := r.length
+	if r.recording {
+		length = r.next - r.start
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	putU := func(v uint64) {
+		n := binary.PutUvarint(buf, v)
+		bw.Write(buf[:n])
+	}
+	putI := func(v int32) {
+		n := binary.PutVarint(buf, int64(v))
+		bw.Write(buf[:n])
+	}
+
+	bw.Write(recordingMagic)
+	putU(length)
+	putU(uint64(len(r.events)))
+
+	tick := uint64(0)
+	for _, re := range r.events {
+		putU(re.tick - tick)
+		tick = re.tick
+
+		putU(uint64(re.eType))
+		putU(uint64(re.which))
+		putU(uint64(re.state))
+		putU(uint64(re.dir))
+		putU(uint64(re.keyScan))
+		putU(uint64(re.keyCode))
+		putU(uint64(re.keyMotif))
+		putU(uint64(re.clicks))
+		putU(uint64(re.button))
+		putU(uint64(re.repeat))
+		putU(uint64(re.axis))
+		putU(uint64(math.Float32bits(re.axisValue)))
+		putU(math.Float64bits(re.scrollX))
+		putU(math.Float64bits(re.scrollY))
+		putU(uint64(re.char))
+		putI(re.mx)
+		putI(re.my)
+		putI(re.mxRel)
+		putI(re.myRel)
+	}
+
+	return bw.Flush()
+}
+
+func decodeRecording(reader io.Reader) (events []recordedEvent, length uint64, err error) {
+	br := bufio.NewReader(reader)
+
+	magic := make([]byte, len(recordingMagic))
+	_, err = io.ReadFull(br, magic)
+	if err != nil || string(magic) != string(recordingMagic) {
+		return nil, 0, errors.New("InputRecorder: not a recording, or an unknown version")
+	}
+
+	// The first error is kept and the remaining reads are skipped.
+	getU := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(br)
+		return v
+	}
+	getI := func() int32 {
+		if err != nil {
+			return 0
+		}
+		var v int64
+		v, err = binary.ReadVarint(br)
+		return int32(v)
+	}
+
+	length = getU()
+	count := getU()
+
+	tick := uint64(0)
+	for i := uint64(0); i < count && err == nil; i++ {
+		re := recordedEvent{}
+
+		tick += getU()
+		re.tick = tick
+
+		re.eType = uint32(getU())
+		re.which = uint32(getU())
+		re.state = uint32(getU())
+		re.dir = uint32(getU())
+		re.keyScan = uint32(getU())
+		re.keyCode = uint32(getU())
+		re.keyMotif = uint32(getU())
+		re.clicks = uint8(getU())
+		re.button = uint8(getU())
+		re.repeat = uint8(getU())
+		re.axis = uint8(getU())
+		re.axisValue = math.Float32frombits(uint32(getU()))
+		re.scrollX = math.Float64frombits(getU())
+		re.scrollY = math.Float64frombits(getU())
+		re.char = rune(getU())
+		re.mx = getI()
+		re.my = getI()
+		re.mxRel = getI()
+		re.myRel = getI()
+
+		events = append(events, re)
+	}
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("InputRecorder: corrupt recording: %v", err)
+	}
+
+	return events, length, nil
+}
@@ -0,0 +1,64 @@
+package io
+
+import "github.com/wdevore/Ranger-Go-IGE/api"
+
+const (
+	// DoubleClickInterval is the longest time, in nanoseconds, between
+	// presses that continue a count.
+	DoubleClickInterval = int64(500000000)
+	// DoubleClickDistance is the furthest, in device pixels, the mouse
+	// may move between presses that continue a count.
+	DoubleClickDistance = int32(4)
+)
+
+type clickCounter struct {
+	interval int64
+	distance int32
+
+	button uint8
+	x, y   int32
+	time   int64
+	count  uint8
+}
+
+// NewClickCounter creates a counter. See DoubleClickInterval and
+// DoubleClickDistance for typical values.
+func NewClickCounter(interval int64, distance int32) api.IClickCounter {
+	o := new(clickCounter)
+	o.interval = interval
+	o.distance = distance
+	return o
+}
+
+func (c *clickCounter) Press(button uint8, x, y int32, now int64) uint8 {
+	continues := c.count > 0 &&
+		button == c.button &&
+		now-c.time <= c.interval &&
+		abs(x-c.x) <= c.distance && abs(y-c.y) <= c.distance
+
+	if continues && c.count < 255 {
+		c.count++
+	} else if !continues {
+		c.count = 1
+	}
+
+	c.button = button
+	c.x, c.y = x, y
+	c.time = now
+
+	return c.count
+}
+
+func (c *clickCounter) Clicks(button uint8) uint8 {
+	if c.count == 0 || button != c.button {
+		return 1
+	}
+	return c.count
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1,10 +1,14 @@
 package io
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
 
 // Event handles IO events
 type Event struct {
-	eTtimeStamp  uint32
+	eTimestamp   int64
 	eType        uint32
 	eWhich       uint32
 	eState       uint32
@@ -15,8 +19,15 @@ type Event struct {
 	eKeyScancode uint32
 	eKeycode     uint32
 	eKeyModif    uint32
+	eAxis        uint8
+	eAxisValue   float32
+	eScrollX     float64
+	eScrollY     float64
+	eRune        rune
 	mx, my       int32
 	mxRel, myRel int32
+	phase        int
+	target       api.INode
 	handled      bool
 }
 
@@ -28,7 +39,7 @@ func NewEvent() *Event {
 
 // Reset clears all properties
 func (e *Event) Reset() {
-	e.eTtimeStamp = 0
+	e.eTimestamp = 0
 	e.eType = 0
 	e.eWhich = 0
 	e.eState = 0
@@ -39,11 +50,53 @@ func (e *Event) Reset() {
 	e.eKeyScancode = 0
 	e.eKeycode = 0
 	e.eKeyModif = 0
+	e.eAxis = 0
+	e.eAxisValue = 0
+	e.eScrollX = 0
+	e.eScrollY = 0
+	e.eRune = 0
 	e.mx = 0
 	e.my = 0
+	e.mxRel = 0
+	e.myRel = 0
+	e.phase = api.PhaseNone
+	e.target = nil
 	e.handled = false
 }
 
+// CopyFrom sets all properties from another event.
+func (e *Event) CopyFrom(src api.IEvent) {
+	e.eTimestamp = src.GetTimestamp()
+	e.eType = src.GetType()
+	e.eWhich = src.GetWhich()
+	e.eState = src.GetState()
+	e.eClicks = src.GetClicks()
+	e.eButton = src.GetButton()
+	e.eDir = src.GetDirection()
+	e.eRepeat = src.GetRepeat()
+	e.eKeyScancode = src.GetKeyScan()
+	e.eKeycode = src.GetKeyCode()
+	e.eKeyModif = src.GetKeyMotif()
+	e.eAxis, e.eAxisValue = src.GetAxis()
+	e.eScrollX, e.eScrollY = src.GetScroll()
+	e.eRune = src.GetRune()
+	e.mx, e.my = src.GetMousePosition()
+	e.mxRel, e.myRel = src.GetMouseRelMovement()
+	e.phase = src.GetPhase()
+	e.target = src.GetTarget()
+	e.handled = src.BeenHandled()
+}
+
+// SetTimestamp sets
+func (e *Event) SetTimestamp(t int64) {
+	e.eTimestamp = t
+}
+
+// GetTimestamp gets
+func (e *Event) GetTimestamp() int64 {
+	return e.eTimestamp
+}
+
 // Handled marks event as handled to stop event bubbling
 func (e *Event) Handled(mark bool) {
 	e.handled = mark
@@ -54,6 +107,26 @@ func (e *Event) BeenHandled() bool {
 	return e.handled
 }
 
+// SetPhase sets
+func (e *Event) SetPhase(phase int) {
+	e.phase = phase
+}
+
+// GetPhase gets
+func (e *Event) GetPhase() int {
+	return e.phase
+}
+
+// SetTarget sets
+func (e *Event) SetTarget(target api.INode) {
+	e.target = target
+}
+
+// GetTarget gets
+func (e *Event) GetTarget() api.INode {
+	return e.target
+}
+
 // SetMousePosition set mx, my
 func (e *Event) SetMousePosition(x, y int32) {
 	e.mx = x
@@ -96,6 +169,27 @@ func (e *Event) GetClicks() uint8 {
 	return e.eClicks
 }
 
+// SetScroll sets
+func (e *Event) SetScroll(x, y float64) {
+	e.eScrollX = x
+	e.eScrollY = y
+}
+
+// GetScroll gets
+func (e *Event) GetScroll() (x, y float64) {
+	return e.eScrollX, e.eScrollY
+}
+
+// SetRune sets
+func (e *Event) SetRune(r rune) {
+	e.eRune = r
+}
+
+// GetRune gets
+func (e *Event) GetRune() rune {
+	return e.eRune
+}
+
 // SetButton sets
 func (e *Event) SetButton(eButton uint8) {
 	e.eButton = eButton
@@ -126,6 +220,17 @@ func (e *Event) GetWhich() uint32 {
 	return e.eWhich
 }
 
+// SetAxis sets
+func (e *Event) SetAxis(axis uint8, value float32) {
+	e.eAxis = axis
+	e.eAxisValue = value
+}
+
+// GetAxis gets
+func (e *Event) GetAxis() (axis uint8, value float32) {
+	return e.eAxis, e.eAxisValue
+}
+
 // SetState sets
 func (e *Event) SetState(state uint32) {
 	e.eState = state
@@ -178,14 +283,19 @@ func (e *Event) GetKeyMotif() uint32 {
 
 func (e Event) String() string {
 	s := "----------Event---------\n"
+	s += fmt.Sprintf("Timestamp: %d\n", e.eTimestamp)
 	s += fmt.Sprintf("mx: %d, my: %d\n", e.mx, e.my)
 	s += fmt.Sprintf("mxRel: %d, myRel: %d\n", e.mxRel, e.myRel)
-	s += fmt.Sprintf("Button: %d\n", e.eButton)
+	s += fmt.Sprintf("Button: %d, Clicks: %d\n", e.eButton, e.eClicks)
+	s += fmt.Sprintf("Scroll: %f, %f\n", e.eScrollX, e.eScrollY)
+	s += fmt.Sprintf("Rune: %q\n", e.eRune)
 	s += fmt.Sprintf("Dir: %d\n", e.eDir)
 	s += fmt.Sprintf("Which: %d 0x%0x\n", e.eWhich, e.eWhich)
 	s += fmt.Sprintf("State: %d 0x%0x\n", e.eState, e.eState)
 	s += fmt.Sprintf("KeyScan: %d\n", e.eKeyScancode)
 	s += fmt.Sprintf("KeyCode: %d\n", e.eKeycode)
+	s += fmt.Sprintf("Axis: %d = %f\n", e.eAxis, e.eAxisValue)
+	s += fmt.Sprintf("Phase: %d\n", e.phase)
 	s += fmt.Sprintf("Type: (%d) 0x%0x", e.eType, e.eType)
 	return s
 }
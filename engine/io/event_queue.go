@@ -0,0 +1,66 @@
+package io
+
+import "github.com/wdevore/Ranger-Go-IGE/api"
+
+type eventQueue struct {
+	events []*Event
+
+	coalesceMotion bool
+}
+
+// NewEventQueue creates an empty queue that coalesces mouse motion.
+func NewEventQueue() api.IEventQueue {
+	o := new(eventQueue)
+	o.coalesceMotion = true
+	return o
+}
+
+func (q *eventQueue) Post(event api.IEvent) {
+	if q.coalesceMotion && event.GetType() == api.IOTypeMouseMotion && len(q.events) > 0 {
+		last := q.events[len(q.events)-1]
+		if last.GetType() == api.IOTypeMouseMotion && last.GetState() == event.GetState() {
+			// Keep the latest position but the total movement.
+			rx, ry := last.GetMouseRelMovement()
+			ex, ey := event.GetMouseRelMovement()
+			last.CopyFrom(event)
+			last.SetMouseRelMovement(rx+ex, ry+ey)
+			return
+		}
+	}
+
+	// Events are copied as their sources reuse them. A new copy is
+	// made each time so handlers may keep the events they're given.
+	e := NewEvent()
+	e.CopyFrom(event)
+	q.events = append(q.events, e)
+}
+
+func (q *eventQueue) SetCoalesceMotion(coalesce bool) {
+	q.coalesceMotion = coalesce
+}
+
+func (q *eventQueue) CoalesceMotion() bool {
+	return q.coalesceMotion
+}
+
+func (q *eventQueue) Len() int {
+	return len(q.events)
+}
+
+func (q *eventQueue) Peek(i int) api.IEvent {
+	return q.events[i]
+}
+
+func (q *eventQueue) Consume(i int) {
+	q.events = append(q.events[:i], q.events[i+1:]...)
+}
+
+func (q *eventQueue) Dispatch(route func(event api.IEvent)) {
+	for len(q.events) > 0 {
+		e := q.events[0]
+		q.events[0] = nil
+		q.events = q.events[1:]
+		route(e)
+	}
+	q.events = q.events[:0]
+}
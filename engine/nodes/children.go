@@ -0,0 +1,122 @@
+package nodes
+
+import (
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
+)
+
+// managerOf finds the node manager through the first node, from node
+// upwards, that has a world.
+func managerOf(node api.INode) *nodeManager {
+	for p := node; p != nil; p = p.Parent() {
+		world := p.World()
+		if world != nil {
+			man, _ := world.NodeManager().(*nodeManager)
+			return man
+		}
+	}
+	return nil
+}
+
+// liveManager returns the node manager if node is in a scene on the
+// stage, otherwise nil.
+func liveManager(node api.INode) *nodeManager {
+	man := managerOf(node)
+	if man == nil || !man.IsLive(node) {
+		return nil
+	}
+	return man
+}
+
+// attached queues a child that was just added to a live parent to be
+// entered at the next update, by which time its constructor has built
+// it.
+func attached(child api.INode) {
+	man := liveManager(child.Parent())
+	if man != nil {
+		man.queueEnter(child)
+	}
+}
+
+// detaching exits a child about to be removed from a live parent.
+func detaching(child api.INode) {
+	man := liveManager(child.Parent())
+	if man != nil {
+		man.exitNode(child)
+	}
+}
+
+// Reparent moves node to the end of parent's children. It exits from
+// its old scene and enters the new one as needed. With
+// keepWorldTransform the node's position, rotation and scale are
+// changed so it stays where it was on screen.
+func Reparent(node api.INode, parent api.INode, keepWorldTransform bool) {
+	var world api.IAffineTransform
+	if keepWorldTransform {
+		world = maths.NewTransform()
+		world.SetByTransform(NodeToWorldTransform(node, nil))
+	}
+
+	if node.Parent() != nil {
+		node.Parent().RemoveChild(node)
+	}
+
+	node.SetParent(parent)
+
+	if keepWorldTransform {
+		setWorldTransform(node, world)
+	}
+
+	parent.AddChild(node)
+}
+
+// setWorldTransform sets node's local transform so its world transform
+// matches world. Shear can't be represented and is lost.
+func setWorldTransform(node api.INode, world api.IAffineTransform) {
+	local := maths.NewTransform()
+	maths.Multiply(world, WorldToNodeTransform(node.Parent(), nil), local)
+
+	a, b, c, d, tx, ty := local.Components()
+
+	sx := float32(math.Hypot(float64(a), float64(b)))
+	sy := float32(math.Hypot(float64(c), float64(d)))
+	if a*d-b*c < 0.0 {
+		sy = -sy
+	}
+
+	node.SetPosition(tx, ty)
+	node.SetRotation(math.Atan2(float64(b), float64(a)))
+	node.SetScaleComps(sx, sy)
+	node.SetDirty(true)
+}
+
+// DestroySubtree removes node from its parent, exiting it if it was
+// live, and takes the subtree apart. Every node in it is unregistered
+// as a timing and event target, in case its ExitNode didn't, so
+// nothing keeps the nodes alive.
+func DestroySubtree(node api.INode) {
+	man := managerOf(node)
+
+	if node.Parent() != nil {
+		node.Parent().RemoveChild(node)
+	}
+
+	destroy(node, man)
+}
+
+func destroy(node api.INode, man *nodeManager) {
+	children := append([]api.INode{}, node.Children()...)
+	for _, child := range children {
+		destroy(child, man)
+		node.RemoveChild(child)
+	}
+
+	if man != nil {
+		man.timingTargets.Remove(node)
+		if man.eventTargets != nil {
+			man.eventTargets.Remove(node)
+		}
+	}
+}
@@ -39,14 +39,15 @@ func (t *TransformFilter) Build(world api.IWorld) error {
 }
 
 // Visit is special in that it they provide their own implementation
-func (t *TransformFilter) Visit(transStack api.ITransformStack, interpolation float64) {
+func (t *TransformFilter) Visit(transStack api.ITransformStack, profiler api.IProfiler, interpolation float64) {
 	if !t.IsVisible() {
 		return
 	}
 
 	transStack.Save()
+	nodes.ApplyTint(t, transStack)
 
-	children := t.Children()
+	children := t.DrawOrder()
 
 	for _, child := range children {
 		transStack.Save()
@@ -71,7 +72,7 @@ func (t *TransformFilter) Visit(transStack api.ITransformStack, interpolation fl
 		}
 
 		// Now visit the child with the modified context
-		nodes.Visit(child, transStack, interpolation)
+		nodes.Visit(child, transStack, profiler, interpolation)
 
 		transStack.Restore()
 	}
@@ -35,14 +35,15 @@ func (t *TranslateFilter) Build(world api.IWorld) error {
 // Visit is special in that it they provide their own implementation.
 // Because this is a Translate filter we "filter out" everything
 // but the translation component from the immediate parent.
-func (t *TranslateFilter) Visit(transStack api.ITransformStack, interpolation float64) {
+func (t *TranslateFilter) Visit(transStack api.ITransformStack, profiler api.IProfiler, interpolation float64) {
 	if !t.IsVisible() {
 		return
 	}
 
 	transStack.Save()
+	nodes.ApplyTint(t, transStack)
 
-	children := t.Children()
+	children := t.DrawOrder()
 
 	for _, child := range children {
 		transStack.Save()
@@ -65,7 +66,7 @@ func (t *TranslateFilter) Visit(transStack api.ITransformStack, interpolation fl
 		}
 
 		// Now visit the child with the modified context
-		nodes.Visit(child, transStack, interpolation)
+		nodes.Visit(child, transStack, profiler, interpolation)
 
 		transStack.Restore()
 	}
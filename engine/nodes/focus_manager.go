@@ -0,0 +1,234 @@
+package nodes
+
+import (
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+)
+
+type focusManager struct {
+	man *nodeManager
+
+	focused api.INode
+
+	from api.IPoint
+	to   api.IPoint
+}
+
+func newFocusManager(man *nodeManager) *focusManager {
+	o := new(focusManager)
+	o.man = man
+	o.from = geometry.NewPoint()
+	o.to = geometry.NewPoint()
+	return o
+}
+
+func (f *focusManager) Focused() api.INode {
+	return f.focused
+}
+
+func (f *focusManager) SetFocus(node api.INode) {
+	if node == f.focused {
+		return
+	}
+
+	if f.focused != nil {
+		blurred := f.focused
+		f.focused = nil
+		blurred.(api.IFocusable).Blur()
+	}
+
+	if node == nil {
+		return
+	}
+
+	focusable, isFocusable := node.(api.IFocusable)
+	if isFocusable {
+		f.focused = node
+		focusable.Focus()
+	}
+}
+
+func (f *focusManager) ClearFocus() {
+	f.SetFocus(nil)
+}
+
+// exited clears the focus when the focused node leaves the stage.
+func (f *focusManager) exited(node api.INode) {
+	if node == f.focused {
+		f.ClearFocus()
+	}
+}
+
+func (f *focusManager) Focusables() []api.INode {
+	focusables := []api.INode{}
+
+	if f.man.scenes == nil {
+		return focusables
+	}
+
+	// A scene can briefly occupy both slots while it transitions.
+	visited := []api.INode{}
+
+	for _, node := range f.man.scenes.Children() {
+		scene, isScene := node.(api.IScene)
+		if isScene && scene.CurrentState() == api.SceneOnStage && !inPath(visited, node) {
+			visited = append(visited, node)
+			focusables = collectFocusables(node, focusables)
+		}
+	}
+
+	return focusables
+}
+
+func collectFocusables(node api.INode, focusables []api.INode) []api.INode {
+	if !node.IsVisible() {
+		return focusables
+	}
+
+	focusable, isFocusable := node.(api.IFocusable)
+	if isFocusable && focusable.Focusable() {
+		focusables = append(focusables, node)
+	}
+
+	for _, child := range node.Children() {
+		focusables = collectFocusables(child, focusables)
+	}
+
+	return focusables
+}
+
+func (f *focusManager) Navigate(direction int) bool {
+	focusables := f.Focusables()
+	count := len(focusables)
+	if count == 0 {
+		return false
+	}
+
+	index := -1
+	for i, node := range focusables {
+		if node == f.focused {
+			index = i
+			break
+		}
+	}
+
+	var next api.INode
+
+	switch {
+	case direction == api.FocusNext:
+		next = focusables[(index+1)%count]
+	case direction == api.FocusPrevious:
+		if index < 0 {
+			index = 0
+		}
+		next = focusables[(index-1+count)%count]
+	case index < 0:
+		next = focusables[0]
+	default:
+		next = f.nearest(focusables, direction)
+	}
+
+	if next == nil || next == f.focused {
+		return false
+	}
+
+	f.SetFocus(next)
+
+	return true
+}
+
+// nearest picks the closest node in the direction, measured in
+// world-space between node origins. Straying off the direction's axis
+// costs twice as much as distance along it.
+func (f *focusManager) nearest(focusables []api.INode, direction int) api.INode {
+	MapNodeToWorld(f.focused, f.from)
+
+	var best api.INode
+	bestScore := float32(math.MaxFloat32)
+
+	for _, node := range focusables {
+		if node == f.focused {
+			continue
+		}
+
+		MapNodeToWorld(node, f.to)
+		dx := f.to.X() - f.from.X()
+		dy := f.to.Y() - f.from.Y()
+
+		var along, across float32
+		switch direction {
+		case api.FocusRight:
+			along, across = dx, dy
+		case api.FocusLeft:
+			along, across = -dx, dy
+		case api.FocusUp:
+			along, across = dy, dx
+		case api.FocusDown:
+			along, across = -dy, dx
+		}
+
+		if along <= 0.0 {
+			continue
+		}
+
+		score := along + 2.0*float32(math.Abs(float64(across)))
+		if score < bestScore {
+			best = node
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+func (f *focusManager) Handle(event api.IEvent) bool {
+	direction := -1
+
+	switch event.GetType() {
+	case api.IOTypeKeyboard:
+		if event.GetState() == api.KeyRelease {
+			return false
+		}
+
+		switch event.GetKeyCode() {
+		case api.KeyTab:
+			direction = api.FocusNext
+			if event.GetKeyMotif()&api.ModShift != 0 {
+				direction = api.FocusPrevious
+			}
+		case api.KeyUp:
+			direction = api.FocusUp
+		case api.KeyDown:
+			direction = api.FocusDown
+		case api.KeyLeft:
+			direction = api.FocusLeft
+		case api.KeyRight:
+			direction = api.FocusRight
+		}
+	case api.IOTypeGamepadButtonDown:
+		switch event.GetButton() {
+		case api.GamepadButtonDpadUp:
+			direction = api.FocusUp
+		case api.GamepadButtonDpadDown:
+			direction = api.FocusDown
+		case api.GamepadButtonDpadLeft:
+			direction = api.FocusLeft
+		case api.GamepadButtonDpadRight:
+			direction = api.FocusRight
+		}
+	}
+
+	if direction < 0 {
+		return false
+	}
+
+	// Until something has focus the arrows and d-pad belong to the game,
+	// only Tab starts navigating.
+	if f.focused == nil && direction != api.FocusNext && direction != api.FocusPrevious {
+		return false
+	}
+
+	return f.Navigate(direction)
+}
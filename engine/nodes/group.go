@@ -1,10 +1,21 @@
 package nodes
 
-import "github.com/wdevore/Ranger-Go-IGE/api"
+import (
+	"sort"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
 
 // Group holds the children properties and methods.
 type Group struct {
 	children []api.INode
+
+	// The children sorted for drawing. See DrawOrder.
+	drawOrder []api.INode
+	// The sort is valid while orderValid is set and the sorting layers
+	// are still at orderGeneration.
+	orderValid      bool
+	orderGeneration int
 }
 
 func (g *Group) initializeGroup() {
@@ -17,19 +28,60 @@ func (g *Group) Children() []api.INode {
 	return g.children
 }
 
-// AddChild adds a node to this node
+// AddChild adds a node to this node. The child's parent should be set
+// first so, if the parent is in a scene on the stage, the child is
+// entered at the next update.
 func (g *Group) AddChild(child api.INode) {
 	if child != nil {
 		g.children = append(g.children, child)
+		g.orderValid = false
+		attached(child)
 	}
 }
 
 // PrependChild adds the give node to the start of the collection rather than the end.
 func (g *Group) PrependChild(child api.INode) {
-	if child != nil {
-		front := []api.INode{child}
-		g.children = append(front, g.children[1:]...)
+	g.InsertChildAt(child, 0)
+}
+
+// InsertChildAt inserts a node before the child at index. An index
+// past the end appends the node.
+func (g *Group) InsertChildAt(child api.INode, index int) {
+	if child == nil {
+		return
+	}
+
+	if index < 0 {
+		index = 0
+	} else if index > len(g.children) {
+		index = len(g.children)
 	}
+
+	g.children = append(g.children, nil)
+	copy(g.children[index+1:], g.children[index:])
+	g.children[index] = child
+	g.orderValid = false
+
+	attached(child)
+}
+
+// RemoveChild removes a node and clears its parent. If it was in a
+// scene on the stage it exits first. Returns false if the node isn't a
+// child.
+func (g *Group) RemoveChild(child api.INode) bool {
+	for i, c := range g.children {
+		if c == child {
+			detaching(child)
+
+			g.children = append(g.children[:i], g.children[i+1:]...)
+			g.orderValid = false
+
+			child.SetParent(nil)
+			return true
+		}
+	}
+
+	return false
 }
 
 // GetChildByID finds an INode by ID.
@@ -85,6 +137,8 @@ func (g *Group) InsertAndShift(newNode api.INode, width int) api.INode {
 		g.children = append(shift, g.children[1:width-1]...)
 	}
 
+	g.orderValid = false
+
 	return r
 }
 
@@ -96,7 +150,34 @@ func (g *Group) RemoveLast() api.INode {
 	if l > 0 {
 		n = g.children[l-1]
 		g.children = g.children[:l-1]
+		g.orderValid = false
 	}
 
 	return n
 }
+
+// DrawOrder returns the children in the order they are drawn: by
+// sorting layer and then z-index. Children that tie keep the order they
+// were added in. The sort is kept until the children, a child's
+// z-index or layer, or the sorting layers change.
+func (g *Group) DrawOrder() []api.INode {
+	layers := layersOf(g.children)
+	if g.orderValid && g.orderGeneration == layers.generationOf() {
+		return g.drawOrder
+	}
+
+	g.drawOrder = append(g.drawOrder[:0], g.children...)
+	sort.SliceStable(g.drawOrder, func(i, j int) bool {
+		return layers.drawsBefore(g.drawOrder[i], g.drawOrder[j])
+	})
+
+	g.orderValid = true
+	g.orderGeneration = layers.generationOf()
+
+	return g.drawOrder
+}
+
+// InvalidateDrawOrder forces DrawOrder to sort again.
+func (g *Group) InvalidateDrawOrder() {
+	g.orderValid = false
+}
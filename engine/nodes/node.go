@@ -3,6 +3,7 @@ package nodes
 import (
 	"fmt"
 	"log"
+	"reflect"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
 	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
@@ -28,6 +29,14 @@ type Node struct {
 	Group
 
 	bounds api.IRectangle
+
+	zIndex       int
+	sortingLayer string
+
+	tags []string
+
+	opacity float32
+	tint    api.IPalette
 }
 
 // NewNode constructs a raw base node. Only the Engine should
@@ -53,6 +62,7 @@ func (n *Node) Initialize(name string) {
 	ids++
 	n.name = name
 	n.visible = true
+	n.opacity = 1.0
 	n.dirty = true
 
 	n.bounds = geometry.NewRectangle()
@@ -77,13 +87,22 @@ func (n *Node) InitializeWithID(id int, name string) {
 	n.id = id
 	n.name = name
 	n.visible = true
+	n.opacity = 1.0
 	n.dirty = true
 }
 
 var currentAtlas api.IAtlasX
 
+// InvalidateAtlas forces the next Visit to Use() its atlas again. It is
+// needed whenever something else, like a shader reload, has changed
+// the bound program.
+func InvalidateAtlas() {
+	currentAtlas = nil
+}
+
 // Visit traverses "down" the heirarchy while space-mappings traverses upward.
-func Visit(node api.INode, transStack api.ITransformStack, interpolation float64) {
+// Draws are timed by profiler, which may be nil.
+func Visit(node api.INode, transStack api.ITransformStack, profiler api.IProfiler, interpolation float64) {
 	// Checking visibility here would cause any children that are visible
 	// to not be rendered.
 	// TODO Add parent and children flags for individual control.
@@ -93,6 +112,7 @@ func Visit(node api.INode, transStack api.ITransformStack, interpolation float64
 	}
 
 	transStack.Save()
+	ApplyTint(node, transStack)
 
 	// Because position and angles are dependent
 	// on lerping we perform interpolation first.
@@ -120,7 +140,15 @@ func Visit(node api.INode, transStack api.ITransformStack, interpolation float64
 				atlas.Use()
 				currentAtlas = atlas
 			}
-			nodeRender.Draw(model)
+			atlas.SetTint(transStack.Tint())
+
+			if profiler != nil && profiler.Enabled() {
+				mark := profiler.BeginDraw()
+				nodeRender.Draw(model)
+				profiler.EndDraw(reflect.TypeOf(node).String(), mark)
+			} else {
+				nodeRender.Draw(model)
+			}
 		}
 	} else {
 		log.Fatalf("Node: oops, %s doesn't implement IRender.Draw method", node)
@@ -130,15 +158,15 @@ func Visit(node api.INode, transStack api.ITransformStack, interpolation float64
 	// Some of the children may still be visible.
 	// Note: if you want the parent AND children to be invisible then you
 	// need to bubble visibility to parent and children.
-	children := node.Children()
+	children := node.DrawOrder()
 
 	if len(children) > 0 {
 		for _, child := range children {
 			filter, isFilterType := child.(api.IFilter)
 			if isFilterType {
-				filter.Visit(transStack, interpolation)
+				filter.Visit(transStack, profiler, interpolation)
 			} else {
-				Visit(child, transStack, interpolation)
+				Visit(child, transStack, profiler, interpolation)
 			}
 		}
 	}
@@ -171,6 +199,101 @@ func (n *Node) SetVisible(visible bool) {
 	n.visible = visible
 }
 
+// Opacity is the node's own opacity, before its parents' are applied.
+func (n *Node) Opacity() float32 {
+	return n.opacity
+}
+
+// SetOpacity fades the node and its children. It's clamped to 0->1.
+func (n *Node) SetOpacity(opacity float32) {
+	if opacity < 0.0 {
+		opacity = 0.0
+	} else if opacity > 1.0 {
+		opacity = 1.0
+	}
+	n.opacity = opacity
+}
+
+// Tint is the node's own tint, before its parents' are applied. A node
+// without one returns a shared white palette, use SetTint rather than
+// changing it.
+func (n *Node) Tint() api.IPalette {
+	if n.tint == nil {
+		return white
+	}
+	return n.tint
+}
+
+// SetTint colors the node and its children. nil is white.
+func (n *Node) SetTint(tint api.IPalette) {
+	n.tint = tint
+}
+
+// ZIndex orders the node among its siblings in the same sorting layer.
+func (n *Node) ZIndex() int {
+	return n.zIndex
+}
+
+// SetZIndex moves the node forward (higher) or backward (lower)
+// without re-parenting it.
+func (n *Node) SetZIndex(z int) {
+	if z != n.zIndex {
+		n.zIndex = z
+		n.invalidateParentOrder()
+	}
+}
+
+// SortingLayer is the name of the node's sorting layer.
+func (n *Node) SortingLayer() string {
+	return n.sortingLayer
+}
+
+// SetSortingLayer moves the node to a named layer.
+func (n *Node) SetSortingLayer(name string) {
+	if name != n.sortingLayer {
+		n.sortingLayer = name
+		n.invalidateParentOrder()
+	}
+}
+
+// Tags returns the node's tags.
+func (n *Node) Tags() []string {
+	return n.tags
+}
+
+// HasTag checks if the node is tagged with tag.
+func (n *Node) HasTag(tag string) bool {
+	for _, t := range n.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag tags the node. Adding a tag twice has no effect.
+func (n *Node) AddTag(tag string) {
+	if !n.HasTag(tag) {
+		n.tags = append(n.tags, tag)
+	}
+}
+
+// RemoveTag removes a tag from the node.
+func (n *Node) RemoveTag(tag string) {
+	for i, t := range n.tags {
+		if t == tag {
+			n.tags = append(n.tags[:i], n.tags[i+1:]...)
+			return
+		}
+	}
+}
+
+func (n *Node) invalidateParentOrder() {
+	if n.parent != nil {
+		n.parent.InvalidateDrawOrder()
+	}
+}
+
 // Interpolate is used for blending time based properties.
 func (n *Node) Interpolate(interpolation float64) {
 	// fmt.Println("Node Interpolate on: ", n)
@@ -6,6 +6,8 @@ import (
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
 	"github.com/wdevore/Ranger-Go-IGE/engine/display"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
 	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
 )
 
@@ -17,10 +19,38 @@ type nodeManager struct {
 	stack *nodeStack
 
 	transStack api.ITransformStack
+	// Times the draws, passed down by Visit
+	profiler api.IProfiler
 
 	timingTargets api.INodeList
 	eventTargets  api.INodeList
 
+	world api.IWorld
+
+	// The topmost node under the pointer as of the last pointer event
+	hovered api.INode
+	// The node that handled the button press being held, see capture
+	captured       api.INode
+	capturedButton uint8
+	// Carries the synthesized enter, leave and hover events
+	synthetic *io.Event
+
+	viewPoint  api.IPoint
+	localPoint api.IPoint
+
+	// Inverse transforms cached by the hit test, see worldToNode
+	inverses map[api.INode]*inverse
+	hitTick  uint64
+
+	focus *focusManager
+
+	// Scenes that have been entered and not yet exited
+	live map[api.INode]bool
+	// Nodes attached to live parents waiting to be entered
+	entering []api.INode
+
+	layers sortingLayers
+
 	root   api.INode
 	scenes api.INode
 
@@ -28,7 +58,6 @@ type nodeManager struct {
 	currentScene api.INode
 
 	projection *display.Projection
-	viewport   *display.Viewport
 
 	preM4  api.IMatrix4
 	postM4 api.IMatrix4
@@ -50,15 +79,23 @@ func NewNodeManager() api.INodeManager {
 	o.timingTargets = NewNodeList()
 	o.eventTargets = NewNodeList()
 
+	o.synthetic = io.NewEvent()
+	o.viewPoint = geometry.NewPoint()
+	o.localPoint = geometry.NewPoint()
+	o.inverses = make(map[api.INode]*inverse)
+	o.hitTick = 1
+	o.focus = newFocusManager(o)
+	o.live = make(map[api.INode]bool)
+
 	o.preM4 = maths.NewMatrix4()
 	o.postM4 = maths.NewMatrix4()
 	return o
 }
 
 func (n *nodeManager) Configure(world api.IWorld) error {
-	// Setup view/projection matrix composition
+	n.world = world
 
-	n.configureSpaces(world)
+	n.profiler = world.Profiler()
 
 	identity := maths.NewMatrix4()
 
@@ -66,22 +103,35 @@ func (n *nodeManager) Configure(world api.IWorld) error {
 	// placed on the stack on the first save() call.
 	n.transStack.Initialize(identity)
 
-	dvr := world.Properties().Window.DeviceRes
-	n.postM4.SetTranslate3Comp(float32(-dvr.Width/2+10.0), float32(-dvr.Height/2)+10.0, 0.0)
+	// Setup view/projection matrix composition
+	n.Resize()
 
 	return nil
 }
 
-func (n *nodeManager) configureSpaces(world api.IWorld) {
-	wp := world.Properties().Window
+func (n *nodeManager) Resize() {
+	n.configureSpaces(n.world)
 
+	dvr := n.world.Properties().Window.DeviceRes
+	n.postM4.SetTranslate3Comp(float32(-dvr.Width/2+10.0), float32(-dvr.Height/2)+10.0, 0.0)
+}
+
+func (n *nodeManager) configureSpaces(world api.IWorld) {
 	// ------------------------------------------------------------
 	// Viewport device-space
 	// ------------------------------------------------------------
-	n.viewport = display.NewViewport()
+	// The World sizes the viewport based on the ScalePolicy.
+	viewport := world.Viewport()
+
+	// There is no GL context when running headless or in software.
+	backend := world.Properties().Engine.Backend
+	if backend != api.BackendHeadless && backend != api.BackendSoftware {
+		viewport.Apply()
+	}
 
-	n.viewport.SetDimensions(0, 0, wp.DeviceRes.Width, wp.DeviceRes.Height)
-	n.viewport.Apply()
+	// The projection/view may have changed (i.e. a resize) so force
+	// the next Visit to Use() its atlas again.
+	InvalidateAtlas()
 
 	camera := world.Properties().Camera
 
@@ -91,8 +141,9 @@ func (n *nodeManager) configureSpaces(world api.IWorld) {
 	offsetX := float32(0.0)
 	offsetY := float32(0.0)
 	if camera.Centered {
-		offsetX = float32(wp.DeviceRes.Width) / 2.0
-		offsetY = float32(wp.DeviceRes.Height) / 2.0
+		viewWidth, viewHeight := viewport.ViewSize()
+		offsetX = viewWidth / 2.0
+		offsetY = viewHeight / 2.0
 		// fmt.Println("NodeManager.configureProjections: center offset: ", offsetX, ",", offsetY)
 	}
 
@@ -131,7 +182,7 @@ func (n *nodeManager) Begin() error {
 	// starts with only two scenes.
 	n.nextScene = n.stack.top()
 
-	n.scenes = n.root.GetChildByName("Scenes")
+	n.scenes = FindPath(n.root, "Scenes")
 
 	return nil
 }
@@ -210,7 +261,7 @@ func (n *nodeManager) continueVisit(interpolation float64) bool {
 	// Now that visible Scene(s) have been attached/detached to the main Scene
 	// node we can Visit the "Root" node.
 	// -------------------------------------------------------
-	Visit(n.root, n.transStack, interpolation)
+	Visit(n.root, n.transStack, n.profiler, interpolation)
 
 	// When the current scene is the last scene to exit the stage
 	// then the game is over.
@@ -284,6 +335,11 @@ func (n *nodeManager) ReplaceNode(node api.INode) {
 // --------------------------------------------------------------------------
 
 func (n *nodeManager) Update(msPerUpdate, secPerUpdate float64) {
+	// Nodes may move so the hit test's inverse transforms are stale.
+	n.hitTick++
+
+	n.enterQueued()
+
 	for _, target := range *n.timingTargets.Items() {
 		if target != nil {
 			target.Update(msPerUpdate, secPerUpdate)
@@ -311,20 +367,76 @@ func (n *nodeManager) UnRegisterEventTarget(target api.INode) {
 	n.eventTargets.Remove(target)
 }
 
+// RouteEvents dispatches pointer events to the topmost node under the
+// cursor, or to the node that captured the pointer, and key, text and
+// gamepad button events to the focused node, see dispatch. Anything
+// still unhandled is offered to the registered targets, in registration
+// order, until one handles it. Nodes that already saw the event are
+// skipped. Focus events that no target handled may then navigate the
+// focus. Along the path only nodes implementing api.IEventReceiver, or
+// api.IFocusable, are handed events.
 func (n *nodeManager) RouteEvents(event api.IEvent) {
 	if n.eventTargets == nil {
 		return
 	}
 
-	for _, target := range *n.eventTargets.Items() {
-		if target != nil {
-			handled := target.Handle(event)
+	var path []api.INode
+
+	eventType := event.GetType()
+
+	switch {
+	case n.root != nil && n.world != nil && isPointerEvent(eventType):
+		target := n.HitTest(event.GetMousePosition())
+		n.trackHover(target, event)
+
+		if n.capturing(eventType) {
+			path = []api.INode{n.captured}
+			if n.deliverCaptured(event) {
+				return
+			}
+		} else if target != nil {
+			path = pathTo(target)
+
+			if eventType == api.IOTypeMouseButtonDown {
+				n.focusOnPress(path)
+			}
 
-			if handled {
-				break
+			handler := n.dispatch(event, path)
+			if handler != nil {
+				n.capture(handler, event)
+				return
+			}
+		}
+	case isFocusEvent(eventType):
+		focused := n.focus.Focused()
+		if focused != nil {
+			path = pathTo(focused)
+			if n.dispatch(event, path) != nil {
+				return
 			}
 		}
+
+		if !n.offer(event, path) {
+			n.focus.Handle(event)
+		}
+		return
 	}
+
+	n.offer(event, path)
+}
+
+// offer hands the event to the registered targets, skipping those on
+// the path that already saw it, and reports whether one handled it.
+func (n *nodeManager) offer(event api.IEvent, path []api.INode) bool {
+	for _, target := range *n.eventTargets.Items() {
+		if target != nil && !(inPath(path, target) && receives(target)) {
+			if target.Handle(event) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func (n *nodeManager) setNextNode() {
@@ -354,6 +466,16 @@ func (n *nodeManager) End() {
 	}
 
 	n.eventTargets = nil
+	n.hovered = nil
+	n.captured = nil
+	n.inverses = make(map[api.INode]*inverse)
+	n.focus.focused = nil
+	n.live = make(map[api.INode]bool)
+	n.entering = nil
+}
+
+func (n *nodeManager) Focus() api.IFocusManager {
+	return n.focus
 }
 
 // -----------------------------------------------------
@@ -365,7 +487,10 @@ func (n *nodeManager) enterScene(node api.INode) {
 	scene, _ := node.(api.IScene)
 	scene.EnterScene(n)
 
-	children := node.Children()
+	// Children added from now on, including by EnterNode, are queued.
+	n.live[node] = true
+
+	children := append([]api.INode{}, node.Children()...)
 	for _, child := range children {
 		n.enterNode(child)
 	}
@@ -373,18 +498,56 @@ func (n *nodeManager) enterScene(node api.INode) {
 
 func (n *nodeManager) enterNode(node api.INode) {
 	// fmt.Println("NodeManager: enterNode ", node)
+	// Children added by EnterNode are queued, so aren't entered twice.
+	children := append([]api.INode{}, node.Children()...)
+
 	node.EnterNode(n)
 
-	children := node.Children()
 	for _, child := range children {
 		n.enterNode(child)
 	}
 }
 
+// queueEnter queues node unless it, or a parent, is already queued.
+func (n *nodeManager) queueEnter(node api.INode) {
+	for p := node; p != nil; p = p.Parent() {
+		if inPath(n.entering, p) {
+			return
+		}
+	}
+
+	n.entering = append(n.entering, node)
+}
+
+// dequeueEnter removes node from the queue. It returns false if node
+// wasn't queued.
+func (n *nodeManager) dequeueEnter(node api.INode) bool {
+	for i, queued := range n.entering {
+		if queued == node {
+			n.entering = append(n.entering[:i], n.entering[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// enterQueued enters the queued nodes, including any queued meanwhile.
+func (n *nodeManager) enterQueued() {
+	for len(n.entering) > 0 {
+		node := n.entering[0]
+		n.entering = n.entering[1:]
+		n.enterNode(node)
+	}
+	n.entering = nil
+}
+
 func (n *nodeManager) exitScene(node api.INode) bool {
 	// fmt.Println("NodeManager: exitScene ", node)
 	scene, _ := node.(api.IScene)
+	delete(n.live, node)
 	pooled := scene.ExitScene(n)
+	n.focus.exited(node)
+	n.exitedHover(node)
 
 	children := node.Children()
 	for _, child := range children {
@@ -395,12 +558,38 @@ func (n *nodeManager) exitScene(node api.INode) bool {
 }
 
 func (n *nodeManager) exitNode(node api.INode) {
-	node.ExitNode(n)
+	// A node still queued, and so its children, never entered.
+	n.leave(node, !n.dequeueEnter(node))
+}
+
+func (n *nodeManager) leave(node api.INode, entered bool) {
+	if entered {
+		node.ExitNode(n)
+	}
+	n.focus.exited(node)
+	n.exitedHover(node)
 
 	children := node.Children()
 	for _, child := range children {
-		n.exitNode(child)
+		if entered {
+			n.exitNode(child)
+		} else {
+			n.leave(child, false)
+		}
+	}
+}
+
+func (n *nodeManager) FindNode(path string) api.INode {
+	return FindPath(n.root, path)
+}
+
+func (n *nodeManager) IsLive(node api.INode) bool {
+	for p := node; p != nil; p = p.Parent() {
+		if n.live[p] {
+			return true
+		}
 	}
+	return false
 }
 
 func (n *nodeManager) Debug() {
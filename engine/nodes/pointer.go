@@ -0,0 +1,293 @@
+package nodes
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
+)
+
+// Pointer and focus events travel down the target's parent chain from
+// the root (capture), reach the target and then travel back up
+// (bubble). Only nodes implementing api.ICaptureHandler take part in
+// the capture phase. The target and bubble phases call Handle on the
+// nodes that opted in, see receives. Any node returning true stops the
+// event.
+
+// inverse is a node's cached world-to-node transform, valid for the
+// update it was computed in.
+type inverse struct {
+	transform api.IAffineTransform
+	tick      uint64
+}
+
+func isPointerEvent(eventType uint32) bool {
+	switch eventType {
+	case api.IOTypeMouseMotion, api.IOTypeMouseButtonDown,
+		api.IOTypeMouseButtonUp, api.IOTypeMouseWheel:
+		return true
+	}
+	return false
+}
+
+// isFocusEvent is true for events that go to the focused node.
+func isFocusEvent(eventType uint32) bool {
+	switch eventType {
+	case api.IOTypeKeyboard, api.IOTypeText,
+		api.IOTypeGamepadButtonDown, api.IOTypeGamepadButtonUp:
+		return true
+	}
+	return false
+}
+
+// receives is true for nodes that opted in to routed events, see
+// api.IEventReceiver.
+func receives(node api.INode) bool {
+	if _, isFocusable := node.(api.IFocusable); isFocusable {
+		return true
+	}
+
+	receiver, isReceiver := node.(api.IEventReceiver)
+	return isReceiver && receiver.ReceivesEvents()
+}
+
+// focusOnPress focuses the nearest focusable node along path, if any.
+func (n *nodeManager) focusOnPress(path []api.INode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		focusable, isFocusable := path[i].(api.IFocusable)
+		if isFocusable && focusable.Focusable() {
+			n.focus.SetFocus(path[i])
+			return
+		}
+	}
+}
+
+// HitTest visits the tree in the reverse of draw order, see DrawOrder,
+// so the first node hit is the topmost one. Invisible nodes, and their
+// children, can't be hit. Nodes are tested where they were at the last
+// update.
+func (n *nodeManager) HitTest(dvx, dvy int32) api.INode {
+	if n.root == nil || n.world == nil {
+		return nil
+	}
+
+	MapDeviceToView(n.world, dvx, dvy, n.viewPoint)
+
+	return n.hitNode(n.root)
+}
+
+func (n *nodeManager) hitNode(node api.INode) api.INode {
+	if !node.IsVisible() {
+		return nil
+	}
+
+	children := node.DrawOrder()
+	for i := len(children) - 1; i >= 0; i-- {
+		hit := n.hitNode(children[i])
+		if hit != nil {
+			return hit
+		}
+	}
+
+	if n.contains(node) {
+		return node
+	}
+
+	return nil
+}
+
+// contains tests the view-space point against the node's polygon, in
+// local-space, or else against its bounds which are in parent-space.
+// This is MapDeviceToNode with the device to view mapping done once.
+// A node without a polygon or bounds can't be hit.
+func (n *nodeManager) contains(node api.INode) bool {
+	poly, isPoly := node.(api.IHitPolygon)
+	if isPoly {
+		n.worldToNode(node).TransformCompToPoint(n.viewPoint.X(), n.viewPoint.Y(), n.localPoint)
+		return poly.HitPolygon().PointInside(n.localPoint)
+	}
+
+	bounds := node.Bounds()
+	if bounds == nil || bounds.Width() <= 0.0 || bounds.Height() <= 0.0 {
+		return false
+	}
+
+	parent := node.Parent()
+	if parent == nil {
+		n.localPoint.SetByPoint(n.viewPoint)
+	} else {
+		n.worldToNode(parent).TransformCompToPoint(n.viewPoint.X(), n.viewPoint.Y(), n.localPoint)
+	}
+
+	return bounds.PointInside(n.localPoint)
+}
+
+// worldToNode returns node's inverse transform. Each is calculated once
+// per update however many pointer events are hit tested, so nodes moved
+// by a handler are hit where they were until the next update.
+func (n *nodeManager) worldToNode(node api.INode) api.IAffineTransform {
+	inv := n.inverses[node]
+	if inv == nil {
+		inv = &inverse{transform: maths.NewTransform()}
+		n.inverses[node] = inv
+	}
+
+	if inv.tick != n.hitTick {
+		inv.transform.SetByTransform(WorldToNodeTransform(node, nil))
+		inv.tick = n.hitTick
+	}
+
+	return inv.transform
+}
+
+// dispatch runs the capture, target and bubble phases along path,
+// which runs from the root to the target. It returns the node that
+// stopped the event, or nil.
+func (n *nodeManager) dispatch(event api.IEvent, path []api.INode) api.INode {
+	last := len(path) - 1
+	target := path[last]
+
+	defer func() {
+		event.SetPhase(api.PhaseNone)
+		event.SetTarget(nil)
+	}()
+
+	event.SetTarget(target)
+
+	event.SetPhase(api.PhaseCapture)
+	for _, node := range path[:last] {
+		capture, isCapture := node.(api.ICaptureHandler)
+		if isCapture && capture.HandleCapture(event) {
+			return node
+		}
+	}
+
+	event.SetPhase(api.PhaseTarget)
+	if receives(target) && target.Handle(event) {
+		return target
+	}
+
+	event.SetPhase(api.PhaseBubble)
+	for i := last - 1; i >= 0; i-- {
+		if receives(path[i]) && path[i].Handle(event) {
+			return path[i]
+		}
+	}
+
+	return nil
+}
+
+// The node that handles a button press captures the pointer. It is
+// handed the motion and the release, wherever they are, until the
+// button is released. Hover events are still sent to the hit nodes.
+
+// capture starts a capture if node handled a press.
+func (n *nodeManager) capture(node api.INode, event api.IEvent) {
+	if node != nil && n.captured == nil && event.GetType() == api.IOTypeMouseButtonDown {
+		n.captured = node
+		n.capturedButton = event.GetButton()
+	}
+}
+
+// capturing is true for the events that go to the captured node.
+func (n *nodeManager) capturing(eventType uint32) bool {
+	return n.captured != nil &&
+		(eventType == api.IOTypeMouseMotion || eventType == api.IOTypeMouseButtonUp)
+}
+
+// deliverCaptured hands event to the captured node, releasing the
+// capture if it is the release of the button that started it.
+func (n *nodeManager) deliverCaptured(event api.IEvent) bool {
+	captured := n.captured
+	if event.GetType() == api.IOTypeMouseButtonUp && event.GetButton() == n.capturedButton {
+		n.captured = nil
+	}
+
+	defer func() {
+		event.SetPhase(api.PhaseNone)
+		event.SetTarget(nil)
+	}()
+
+	event.SetTarget(captured)
+	event.SetPhase(api.PhaseTarget)
+
+	return captured.Handle(event)
+}
+
+// trackHover sends leave events to the nodes the pointer moved off,
+// deepest first, and enter events to the nodes it moved onto, outermost
+// first. A node stays entered while the pointer is over any of its
+// children. Motion over a node also sends it a hover event. None of
+// these bubble.
+func (n *nodeManager) trackHover(target api.INode, event api.IEvent) {
+	if target != n.hovered {
+		left := pathTo(n.hovered)
+		entered := pathTo(target)
+
+		for i := len(left) - 1; i >= 0; i-- {
+			if !inPath(entered, left[i]) {
+				n.synthesize(api.IOTypePointerLeave, left[i], event)
+			}
+		}
+
+		for _, node := range entered {
+			if !inPath(left, node) {
+				n.synthesize(api.IOTypePointerEnter, node, event)
+			}
+		}
+
+		n.hovered = target
+	}
+
+	if target != nil && event.GetType() == api.IOTypeMouseMotion {
+		n.synthesize(api.IOTypePointerHover, target, event)
+	}
+}
+
+// exitedHover forgets the hovered or captured node, and the node's
+// inverse transform, when it leaves the stage.
+func (n *nodeManager) exitedHover(node api.INode) {
+	if node == n.hovered {
+		n.hovered = nil
+	}
+	if node == n.captured {
+		n.captured = nil
+	}
+	delete(n.inverses, node)
+}
+
+func (n *nodeManager) synthesize(eventType uint32, node api.INode, cause api.IEvent) {
+	if !receives(node) {
+		return
+	}
+
+	e := n.synthetic
+	e.CopyFrom(cause)
+	e.SetType(eventType)
+	e.SetPhase(api.PhaseTarget)
+	e.SetTarget(node)
+	e.Handled(false)
+
+	node.Handle(e)
+}
+
+// pathTo returns the chain of nodes from the root down to node.
+func pathTo(node api.INode) []api.INode {
+	path := []api.INode{}
+	for p := node; p != nil; p = p.Parent() {
+		path = append(path, p)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+func inPath(path []api.INode, node api.INode) bool {
+	for _, p := range path {
+		if p == node {
+			return true
+		}
+	}
+	return false
+}
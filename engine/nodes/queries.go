@@ -0,0 +1,115 @@
+package nodes
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// FindPath looks up a slash-separated path of child names relative to
+// node, e.g. "Game Layer/Ship". ".." steps up to the parent and a
+// leading "/" starts from the topmost ancestor, which for a node in a
+// scene is the scene. Where siblings share a name the first is taken.
+// Returns nil if any step is missing.
+func FindPath(node api.INode, nodePath string) api.INode {
+	if strings.HasPrefix(nodePath, "/") {
+		for node != nil && node.Parent() != nil {
+			node = node.Parent()
+		}
+	}
+
+	for _, name := range strings.Split(nodePath, "/") {
+		if node == nil {
+			return nil
+		}
+
+		switch name {
+		case "", ".":
+		case "..":
+			node = node.Parent()
+		default:
+			node = node.GetChildByName(name)
+		}
+	}
+
+	return node
+}
+
+// PathOf returns node's path from its topmost ancestor, the inverse of
+// FindPath with a leading "/".
+func PathOf(node api.INode) string {
+	names := []string{}
+	for p := node; p != nil && p.Parent() != nil; p = p.Parent() {
+		names = append([]string{p.Name()}, names...)
+	}
+	return "/" + strings.Join(names, "/")
+}
+
+// FindAll searches node's descendants depth-first, in child order, and
+// returns those matching the predicate. node itself isn't included.
+func FindAll(node api.INode, match func(api.INode) bool) []api.INode {
+	found := []api.INode{}
+	walk(node, func(n api.INode) bool {
+		if match(n) {
+			found = append(found, n)
+		}
+		return true
+	})
+	return found
+}
+
+// FindFirst is FindAll's first match, or nil.
+func FindFirst(node api.INode, match func(api.INode) bool) api.INode {
+	var found api.INode
+	walk(node, func(n api.INode) bool {
+		if match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// walk visits node's descendants until visit returns false. It returns
+// false if it was stopped.
+func walk(node api.INode, visit func(api.INode) bool) bool {
+	for _, child := range node.Children() {
+		if !visit(child) || !walk(child, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindByTag returns the descendants tagged with tag.
+func FindByTag(node api.INode, tag string) []api.INode {
+	return FindAll(node, func(n api.INode) bool {
+		return n.HasTag(tag)
+	})
+}
+
+// FindByName returns the descendants whose names match a glob, e.g.
+// "Enemy*". See path.Match for the syntax. A malformed glob matches
+// nothing.
+func FindByName(node api.INode, glob string) []api.INode {
+	return FindAll(node, func(n api.INode) bool {
+		matched, _ := path.Match(glob, n.Name())
+		return matched
+	})
+}
+
+// FindByType returns the descendants of a type. An interface type,
+// such as reflect.TypeOf((*api.IFocusable)(nil)).Elem(), matches any
+// node implementing it. Otherwise the node's type must be the same,
+// such as reflect.TypeOf(&shipNode{}).
+func FindByType(node api.INode, typ reflect.Type) []api.INode {
+	return FindAll(node, func(n api.INode) bool {
+		if typ.Kind() == reflect.Interface {
+			return reflect.TypeOf(n).Implements(typ)
+		}
+		return reflect.TypeOf(n) == typ
+	})
+}
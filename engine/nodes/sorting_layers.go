@@ -0,0 +1,67 @@
+package nodes
+
+import "github.com/wdevore/Ranger-Go-IGE/api"
+
+// DefaultSortingLayer is the layer of nodes that haven't been given
+// one, or were given one that doesn't exist. Unless it is named in
+// SetSortingLayers it sorts first.
+const DefaultSortingLayer = "Default"
+
+// sortingLayers is a NodeManager's layer table. A nil table has no
+// layers so only z-indices count.
+type sortingLayers struct {
+	order map[string]int
+
+	// generation changes with the layers so every Group sorts again.
+	generation int
+}
+
+func (n *nodeManager) SetSortingLayers(names ...string) {
+	n.layers.order = map[string]int{}
+	for i, name := range names {
+		n.layers.order[name] = i
+	}
+	n.layers.generation++
+}
+
+// layersOf returns the table of the manager the children belong to.
+func layersOf(children []api.INode) *sortingLayers {
+	if len(children) == 0 {
+		return nil
+	}
+
+	man := managerOf(children[0])
+	if man == nil {
+		return nil
+	}
+
+	return &man.layers
+}
+
+func (s *sortingLayers) generationOf() int {
+	if s == nil {
+		return 0
+	}
+	return s.generation
+}
+
+func (s *sortingLayers) orderOf(name string) int {
+	if s == nil {
+		return 0
+	}
+
+	order, ok := s.order[name]
+	if !ok {
+		return s.order[DefaultSortingLayer]
+	}
+	return order
+}
+
+func (s *sortingLayers) drawsBefore(a, b api.INode) bool {
+	layerA := s.orderOf(a.SortingLayer())
+	layerB := s.orderOf(b.SortingLayer())
+	if layerA != layerB {
+		return layerA < layerB
+	}
+	return a.ZIndex() < b.ZIndex()
+}
@@ -10,9 +10,11 @@ var tViewPoint = geometry.NewPoint()
 var comp = maths.NewTransform()
 var out = maths.NewTransform()
 
-// MapDeviceToView maps mouse-space device coordinates to view-space
+// MapDeviceToView maps mouse-space device coordinates to view-space.
+// The viewport's scaling (see Window.ScalePolicy) is removed first.
 func MapDeviceToView(world api.IWorld, dvx, dvy int32, viewPoint api.IPoint) {
-	viewPoint.SetByComp(float32(dvx), float32(dvy))
+	px, py := world.Viewport().DeviceToProjection(float32(dvx), float32(dvy))
+	viewPoint.SetByComp(px, py)
 	viewPoint.MulPoint(world.InvertedViewspace())
 }
 
@@ -73,6 +75,9 @@ func MapNodeToDevice(world api.IWorld, node api.INode, devicePoint api.IPoint) {
 	devicePoint.SetByComp(devicePoint.X(), devicePoint.Y())
 	devicePoint.MulPoint(world.Viewspace())
 
+	dvx, dvy := world.Viewport().ProjectionToDevice(devicePoint.X(), devicePoint.Y())
+	devicePoint.SetByComp(dvx, dvy)
+
 	// world.ViewSpace().TransformCompToPoint(viewPoint.X(), viewPoint.Y(), viewPoint)
 }
 
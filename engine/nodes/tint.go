@@ -0,0 +1,23 @@
+package nodes
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/color"
+)
+
+// white is the tint of nodes that haven't been given one. It's shared
+// so it must not be changed.
+var white = color.NewPaletteFromFloats(1.0, 1.0, 1.0, 1.0)
+
+// ApplyTint multiplies node's opacity and tint into the stack's tint.
+// Call it after Save so Restore removes it once node's children are
+// visited. Filters, which Visit their own children, call it too.
+func ApplyTint(node api.INode, transStack api.ITransformStack) {
+	opacity := node.Opacity()
+	if node.Tint() == white && opacity == 1.0 {
+		return
+	}
+
+	r, g, b, a := node.Tint().Components()
+	transStack.ApplyTint(r, g, b, a*opacity)
+}
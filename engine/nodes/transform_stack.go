@@ -7,6 +7,7 @@ import (
 
 type transformStackItem struct {
 	current api.IMatrix4
+	tint    [4]float32
 }
 
 func newTransformItem() *transformStackItem {
@@ -22,6 +23,9 @@ type transformStack struct {
 	current api.IMatrix4
 	post    api.IMatrix4 // Pre allocated cache
 
+	// The product of the opacities and tints from the root down
+	tint [4]float32
+
 	m4 api.IMatrix4
 }
 
@@ -46,6 +50,7 @@ func (t *transformStack) Initialize(mat api.IMatrix4) {
 
 	// The initial value ready for the top of the stack.
 	t.current.Set(mat)
+	t.tint = [4]float32{1.0, 1.0, 1.0, 1.0}
 }
 
 func (t *transformStack) Apply(aft api.IMatrix4) api.IMatrix4 {
@@ -68,6 +73,7 @@ func (t *transformStack) ApplyAffine(aft api.IAffineTransform) api.IMatrix4 {
 func (t *transformStack) Save() {
 	top := t.stack[t.stackTop]
 	top.current.Set(t.current)
+	top.tint = t.tint
 	t.stackTop++
 }
 
@@ -75,4 +81,16 @@ func (t *transformStack) Restore() {
 	t.stackTop--
 	top := t.stack[t.stackTop]
 	t.current.Set(top.current)
+	t.tint = top.tint
+}
+
+func (t *transformStack) ApplyTint(r, g, b, a float32) {
+	t.tint[0] *= r
+	t.tint[1] *= g
+	t.tint[2] *= b
+	t.tint[3] *= a
+}
+
+func (t *transformStack) Tint() []float32 {
+	return t.tint[:]
 }
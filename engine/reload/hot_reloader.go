@@ -0,0 +1,123 @@
+// Package reload watches resource files during development.
+package reload
+
+import (
+	"io/fs"
+	"time"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// fileState is what a file looked like when last checked.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+type watch struct {
+	fsys   fs.FS
+	files  []string
+	states []fileState
+	reload func() error
+}
+
+type hotReloader struct {
+	enabled  bool
+	interval time.Duration
+	lastPoll time.Time
+	reloads  int
+
+	watches []*watch
+}
+
+// NewHotReloader creates a disabled reloader that polls at most once
+// per interval.
+func NewHotReloader(interval time.Duration) api.IHotReloader {
+	o := new(hotReloader)
+	o.interval = interval
+	return o
+}
+
+func (h *hotReloader) Enabled() bool {
+	return h.enabled
+}
+
+func (h *hotReloader) SetEnabled(enabled bool) {
+	h.enabled = enabled
+}
+
+func (h *hotReloader) Watch(fsys fs.FS, reload func() error, files ...string) {
+	w := &watch{
+		fsys:   fsys,
+		files:  files,
+		states: make([]fileState, len(files)),
+		reload: reload,
+	}
+
+	for i, file := range files {
+		w.states[i] = stat(fsys, file)
+	}
+
+	h.watches = append(h.watches, w)
+}
+
+func (h *hotReloader) Poll() []error {
+	if !h.enabled {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Sub(h.lastPoll) < h.interval {
+		return nil
+	}
+	h.lastPoll = now
+
+	return h.Check()
+}
+
+func (h *hotReloader) Check() []error {
+	var errs []error
+
+	for _, w := range h.watches {
+		changed := false
+		for i, file := range w.files {
+			state := stat(w.fsys, file)
+			// A missing file is usually an editor part way through
+			// saving so it is checked again on the next poll.
+			if state.exists && state.differs(w.states[i]) {
+				w.states[i] = state
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		err := w.reload()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		h.reloads++
+	}
+
+	return errs
+}
+
+func (h *hotReloader) Reloads() int {
+	return h.reloads
+}
+
+func (s fileState) differs(o fileState) bool {
+	return s.exists != o.exists || s.size != o.size || !s.modTime.Equal(o.modTime)
+}
+
+func stat(fsys fs.FS, file string) fileState {
+	info, err := fs.Stat(fsys, file)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size(), exists: true}
+}
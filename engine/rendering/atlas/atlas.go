@@ -1,8 +1,84 @@
 package atlas
 
-import "unsafe"
+import (
+	"path"
+	"unsafe"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
 
 const (
 	floatSize = int(unsafe.Sizeof(float32(0)))
 	uintSize  = int(unsafe.Sizeof(uint32(0)))
 )
+
+// watchShader reloads the shader when its files change. The uniforms
+// are configured again because relinking may move their locations.
+func watchShader(world api.IWorld, shader api.IShader, configureUniforms func() error) {
+	reloader := world.HotReloader()
+	vertexSrc, fragmentSrc := shader.Sources()
+	if reloader == nil || vertexSrc == "" {
+		return
+	}
+
+	fsys := world.FS()
+
+	reloader.Watch(fsys, func() error {
+		err := shader.Reload(fsys)
+		if err != nil {
+			return err
+		}
+		return configureUniforms()
+	}, path.Join(api.ShaderDir, vertexSrc), path.Join(api.ShaderDir, fragmentSrc))
+}
+
+// watchSpriteSheet reloads the sheet when its files change. upload, if
+// given, copies the new image to the atlas's texture.
+func watchSpriteSheet(world api.IWorld, sheet api.ISpriteSheet, upload func()) {
+	reloader := world.HotReloader()
+	if reloader == nil || sheet == nil {
+		return
+	}
+
+	fsys, files := sheet.Files()
+
+	reloader.Watch(fsys, func() error {
+		err := sheet.Reload()
+		if err != nil {
+			return err
+		}
+		if upload != nil {
+			upload()
+		}
+		return nil
+	}, files...)
+}
+
+// tint is embedded by the atlases to implement IAtlasX.SetTint.
+type tint struct {
+	// nil is untinted
+	rgba []float32
+
+	store  [4]float32
+	tinted [4]float32
+}
+
+func (t *tint) SetTint(rgba []float32) {
+	if rgba == nil || (rgba[0] == 1.0 && rgba[1] == 1.0 && rgba[2] == 1.0 && rgba[3] == 1.0) {
+		t.rgba = nil
+		return
+	}
+	copy(t.store[:], rgba)
+	t.rgba = t.store[:]
+}
+
+// apply returns color multiplied by the tint.
+func (t *tint) apply(color []float32) []float32 {
+	if t.rgba == nil {
+		return color
+	}
+	for i := range t.tinted {
+		t.tinted[i] = color[i] * t.rgba[i]
+	}
+	return t.tinted[:]
+}
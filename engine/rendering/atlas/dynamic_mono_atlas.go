@@ -2,7 +2,7 @@ package atlas
 
 import (
 	"errors"
-	"path/filepath"
+	"io/fs"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 
@@ -15,6 +15,8 @@ import (
 // A Dyanmic Atlas uses a single color for all shapes
 
 type dynamicMonoAtlas struct {
+	tint
+
 	world api.IWorld
 	burnt bool
 
@@ -41,6 +43,8 @@ type dynamicMonoAtlas struct {
 
 	modelLoc int32
 	colorLoc int32
+	projLoc  int32
+	viewLoc  int32
 
 	dirty bool
 }
@@ -49,6 +53,14 @@ type dynamicMonoAtlas struct {
 // of the same color.
 // This object is also of type IDynamicAtlasX.
 func NewDynamicMonoAtlas(world api.IWorld) api.IAtlasX {
+	if isHeadless(world) {
+		return NewNullAtlas(world)
+	}
+
+	if isSoftware(world) {
+		return NewSoftwareMonoAtlas(world)
+	}
+
 	o := new(dynamicMonoAtlas)
 	o.shapes = []*shape{}
 
@@ -86,7 +98,7 @@ func (s *dynamicMonoAtlas) GetShapeByName(shapeName string) int {
 }
 
 func (s *dynamicMonoAtlas) Configure() error {
-	err := s.configureShaders(s.world.RelativePath(), s.world.Properties())
+	err := s.configureShaders(s.world.FS(), s.world.Properties())
 	if err != nil {
 		return err
 	}
@@ -209,16 +221,27 @@ func (s *dynamicMonoAtlas) Bake() error {
 
 func (s *dynamicMonoAtlas) Use() {
 	s.shader.Use()
+	s.uploadSpaces()
 	gl.BindVertexArray(s.vaoID)
 }
 
+// uploadSpaces copies the World's projection and view matrices to
+// the shader. Both change when the display is resized.
+func (s *dynamicMonoAtlas) uploadSpaces() {
+	pm := s.world.Projection().Matrix()
+	gl.UniformMatrix4fv(s.projLoc, 1, false, &pm[0])
+
+	vm := s.world.Viewspace().Matrix()
+	gl.UniformMatrix4fv(s.viewLoc, 1, false, &vm[0])
+}
+
 func (s *dynamicMonoAtlas) UnUse() {
 	gl.BindVertexArray(0)
 }
 
 // SetColor sets the shader's color
 func (s *dynamicMonoAtlas) SetColor(color []float32) {
-	gl.Uniform4fv(s.colorLoc, 1, &color[0])
+	gl.Uniform4fv(s.colorLoc, 1, &s.apply(color)[0])
 }
 
 func (s *dynamicMonoAtlas) Update() {
@@ -288,20 +311,17 @@ func (s *dynamicMonoAtlas) Render(id int, model api.IMatrix4) {
 	gl.DrawElements(shape.primitiveMode, int32(shape.indicesCount), uint32(gl.UNSIGNED_INT), gl.PtrOffset(shape.indicesOffset))
 }
 
-func (s *dynamicMonoAtlas) configureShaders(relativePath string, config *configuration.Properties) error {
-	dataPath, err := filepath.Abs(relativePath)
-	if err != nil {
-		return err
-	}
-
+func (s *dynamicMonoAtlas) configureShaders(fsys fs.FS, config *configuration.Properties) error {
 	shaders := config.Shaders
 
 	s.shader = rendering.NewShader(shaders.DynamicPixelVertexShaderFile, shaders.DynamicPixelFragmentShaderFile)
-	err = s.shader.Load(dataPath)
+	err := s.shader.Load(fsys)
 	if err != nil {
 		return err
 	}
 
+	watchShader(s.world, s.shader, s.configureUniforms)
+
 	return nil
 }
 
@@ -321,21 +341,17 @@ func (s *dynamicMonoAtlas) configureUniforms() error {
 	}
 
 	// Projection and View
-	projLoc := gl.GetUniformLocation(program, gl.Str("projection\x00"))
-	if projLoc < 0 {
+	s.projLoc = gl.GetUniformLocation(program, gl.Str("projection\x00"))
+	if s.projLoc < 0 {
 		return errors.New("NodeManager: couldn't find 'projection' uniform variable")
 	}
 
-	viewLoc := gl.GetUniformLocation(program, gl.Str("view\x00"))
-	if viewLoc < 0 {
+	s.viewLoc = gl.GetUniformLocation(program, gl.Str("view\x00"))
+	if s.viewLoc < 0 {
 		return errors.New("NodeManager: couldn't find 'view' uniform variable")
 	}
 
-	pm := s.world.Projection().Matrix()
-	gl.UniformMatrix4fv(projLoc, 1, false, &pm[0])
-
-	vm := s.world.Viewspace().Matrix()
-	gl.UniformMatrix4fv(viewLoc, 1, false, &vm[0])
+	s.uploadSpaces()
 
 	return nil
 }
@@ -2,7 +2,7 @@ package atlas
 
 import (
 	"errors"
-	"path/filepath"
+	"io/fs"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 
@@ -15,6 +15,8 @@ import (
 // A DyanmicPixel Atlas uses a single color for all pixels
 //
 type dynamicPixelAtlas struct {
+	tint
+
 	world api.IWorld
 	burnt bool
 
@@ -36,6 +38,8 @@ type dynamicPixelAtlas struct {
 
 	modelLoc int32
 	colorLoc int32
+	projLoc  int32
+	viewLoc  int32
 
 	dirty bool
 }
@@ -44,13 +48,21 @@ type dynamicPixelAtlas struct {
 // of the same color.
 // This is object is also of type IDynamicPixelAtlasX.
 func NewDynamicPixelAtlas(world api.IWorld) api.IAtlasX {
+	if isHeadless(world) {
+		return NewNullAtlas(world)
+	}
+
+	if isSoftware(world) {
+		return NewSoftwarePixelAtlas(world)
+	}
+
 	o := new(dynamicPixelAtlas)
 	o.world = world
 	return o
 }
 
 func (s *dynamicPixelAtlas) Configure() error {
-	err := s.configureShaders(s.world.RelativePath(), s.world.Properties())
+	err := s.configureShaders(s.world.FS(), s.world.Properties())
 	if err != nil {
 		return err
 	}
@@ -142,16 +154,27 @@ func (s *dynamicPixelAtlas) Bake() error {
 
 func (s *dynamicPixelAtlas) Use() {
 	s.shader.Use()
+	s.uploadSpaces()
 	gl.BindVertexArray(s.vaoID)
 }
 
+// uploadSpaces copies the World's projection and view matrices to
+// the shader. Both change when the display is resized.
+func (s *dynamicPixelAtlas) uploadSpaces() {
+	pm := s.world.Projection().Matrix()
+	gl.UniformMatrix4fv(s.projLoc, 1, false, &pm[0])
+
+	vm := s.world.Viewspace().Matrix()
+	gl.UniformMatrix4fv(s.viewLoc, 1, false, &vm[0])
+}
+
 func (s *dynamicPixelAtlas) UnUse() {
 	gl.BindVertexArray(0)
 }
 
 // SetColor sets the shader's color
 func (s *dynamicPixelAtlas) SetColor(color []float32) {
-	gl.Uniform4fv(s.colorLoc, 1, &color[0])
+	gl.Uniform4fv(s.colorLoc, 1, &s.apply(color)[0])
 }
 
 func (s *dynamicPixelAtlas) Update() {
@@ -169,6 +192,11 @@ func (s *dynamicPixelAtlas) SetPixelActiveCount(count int) {
 	s.indicesCount = count
 }
 
+// SetPointSize sets the GL point size used when rendering pixels.
+func (s *dynamicPixelAtlas) SetPointSize(size float32) {
+	gl.PointSize(size)
+}
+
 func (s *dynamicPixelAtlas) SetVertex(x, y float32, index int) {
 	i := index * 3
 	s.vertices[i] = x
@@ -181,20 +209,17 @@ func (s *dynamicPixelAtlas) Render(id int, model api.IMatrix4) {
 	gl.DrawElements(gl.POINTS, int32(s.indicesCount), uint32(gl.UNSIGNED_INT), gl.PtrOffset(0))
 }
 
-func (s *dynamicPixelAtlas) configureShaders(relativePath string, config *configuration.Properties) error {
-	dataPath, err := filepath.Abs(relativePath)
-	if err != nil {
-		return err
-	}
-
+func (s *dynamicPixelAtlas) configureShaders(fsys fs.FS, config *configuration.Properties) error {
 	shaders := config.Shaders
 
 	s.shader = rendering.NewShader(shaders.DynamicPixelVertexShaderFile, shaders.DynamicPixelFragmentShaderFile)
-	err = s.shader.Load(dataPath)
+	err := s.shader.Load(fsys)
 	if err != nil {
 		return err
 	}
 
+	watchShader(s.world, s.shader, s.configureUniforms)
+
 	return nil
 }
 
@@ -214,21 +239,17 @@ func (s *dynamicPixelAtlas) configureUniforms() error {
 	}
 
 	// Projection and View
-	projLoc := gl.GetUniformLocation(program, gl.Str("projection\x00"))
-	if projLoc < 0 {
+	s.projLoc = gl.GetUniformLocation(program, gl.Str("projection\x00"))
+	if s.projLoc < 0 {
 		return errors.New("NodeManager: couldn't find 'projection' uniform variable")
 	}
 
-	viewLoc := gl.GetUniformLocation(program, gl.Str("view\x00"))
-	if viewLoc < 0 {
+	s.viewLoc = gl.GetUniformLocation(program, gl.Str("view\x00"))
+	if s.viewLoc < 0 {
 		return errors.New("NodeManager: couldn't find 'view' uniform variable")
 	}
 
-	pm := s.world.Projection().Matrix()
-	gl.UniformMatrix4fv(projLoc, 1, false, &pm[0])
-
-	vm := s.world.Viewspace().Matrix()
-	gl.UniformMatrix4fv(viewLoc, 1, false, &vm[0])
+	s.uploadSpaces()
 
 	return nil
 }
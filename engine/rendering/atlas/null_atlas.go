@@ -0,0 +1,223 @@
+package atlas
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering"
+)
+
+// The null atlas is used by the Headless backend. It keeps the same
+// shape bookkeeping as the GL atlases, so nodes can add and query
+// shapes, but it never makes a GL call.
+// It satisfies IStaticAtlasX, IDynamicAtlasX, IDynamicPixelAtlasX and
+// ISingleTextureAtlasX so it can stand in for any of the GL atlases.
+
+type nullAtlas struct {
+	tint
+
+	world api.IWorld
+	burnt bool
+
+	atlasName string
+
+	shapes []*shape
+	nextID int
+
+	vertices []float32
+	indices  []uint32
+
+	primitiveMode uint32
+	indexOffset   int
+	indicesCount  int
+	pointSize     float32
+
+	shader      api.IShader
+	spriteSheet api.ISpriteSheet
+	coordIndex  int
+
+	color []float32
+}
+
+// NewNullAtlas creates an atlas that records shapes but renders nothing.
+func NewNullAtlas(world api.IWorld) api.IAtlasX {
+	o := new(nullAtlas)
+	o.shapes = []*shape{}
+	o.world = world
+	o.pointSize = 1.0
+	return o
+}
+
+// NewNullSingleTextureAtlas creates a headless version of a SingleTextureAtlas.
+func NewNullSingleTextureAtlas(atlasName string, spriteSheet api.ISpriteSheet, world api.IWorld) api.IAtlasX {
+	o := NewNullAtlas(world).(*nullAtlas)
+	o.atlasName = atlasName
+	o.spriteSheet = spriteSheet
+	return o
+}
+
+func isHeadless(world api.IWorld) bool {
+	return world.Properties().Engine.Backend == api.BackendHeadless
+}
+
+func (s *nullAtlas) Configure() error {
+	shaders := s.world.Properties().Shaders
+	s.shader = rendering.NewNullShader(shaders.MonoVertexShaderFile, shaders.MonoFragmentShaderFile)
+	return s.shader.Load(s.world.FS())
+}
+
+func (s *nullAtlas) Burnt() bool {
+	return s.burnt
+}
+
+func (s *nullAtlas) Burn() error {
+	err := s.Configure()
+	if err != nil {
+		return err
+	}
+
+	s.Shake()
+
+	err = s.Bake()
+	if err != nil {
+		return err
+	}
+
+	s.burnt = true
+	return nil
+}
+
+func (s *nullAtlas) Shake() {
+	// Only the offsets are tracked. The backing arrays are left alone
+	// because dynamic atlases may have been given data via SetData().
+	indicesOffset := 0
+	vertexOffset := 0
+
+	for _, shape := range s.shapes {
+		shape.indicesOffset = indicesOffset
+		shape.vertexOffset = vertexOffset
+
+		vertexOffset += len(shape.vertices)
+		indicesOffset += len(shape.indices) * uintSize
+	}
+}
+
+func (s *nullAtlas) Bake() error {
+	return nil
+}
+
+func (s *nullAtlas) Use() {
+	if s.shader != nil {
+		s.shader.Use()
+	}
+}
+
+func (s *nullAtlas) UnUse() {
+}
+
+// SetColor captures the color instead of setting a shader uniform.
+func (s *nullAtlas) SetColor(color []float32) {
+	s.color = s.apply(color)
+}
+
+func (s *nullAtlas) Render(id int, model api.IMatrix4) {
+}
+
+// -----------------------------------------------------
+// IStaticAtlasX and IDynamicAtlasX
+// -----------------------------------------------------
+
+// AddShape adds a set of vertices and indices to the atlas.
+func (s *nullAtlas) AddShape(shapeName string, vertices []float32, indices []uint32, mode int) int {
+	shape := shape{
+		id:            s.nextID,
+		shapeName:     shapeName,
+		vertices:      vertices,
+		indices:       indices,
+		indicesCount:  len(indices),
+		primitiveMode: uint32(mode),
+	}
+
+	s.shapes = append(s.shapes, &shape)
+
+	s.nextID++
+
+	return shape.id
+}
+
+func (s *nullAtlas) GetShapeByName(shapeName string) int {
+	for _, shape := range s.shapes {
+		if shape.shapeName == shapeName {
+			return shape.id
+		}
+	}
+
+	return -1
+}
+
+func (s *nullAtlas) FetchVerticesByName(shapeName string) *[]float32 {
+	for _, shape := range s.shapes {
+		if shape.shapeName == shapeName {
+			return &shape.vertices
+		}
+	}
+
+	return nil
+}
+
+func (s *nullAtlas) Update() {
+}
+
+func (s *nullAtlas) SetData(vertices []float32, indices []uint32) {
+	s.vertices = vertices
+	s.indices = indices
+}
+
+func (s *nullAtlas) SetPrimitiveMode(mode int) {
+	s.primitiveMode = uint32(mode)
+}
+
+func (s *nullAtlas) SetIndicesCount(count int) {
+	s.indicesCount = count
+}
+
+func (s *nullAtlas) SetOffset(offset int) {
+	s.indexOffset = offset
+}
+
+func (s *nullAtlas) SetShapeVertex(x, y float32, index, shapeID int) {
+	shape := s.shapes[shapeID]
+
+	i := index * 3
+	shape.vertices[i] = x
+	shape.vertices[i+1] = y
+}
+
+// SetVertex directly sets the backing buffer data.
+func (s *nullAtlas) SetVertex(x, y float32, index int) {
+	i := index * 3
+	s.vertices[i] = x
+	s.vertices[i+1] = y
+}
+
+// -----------------------------------------------------
+// IDynamicPixelAtlasX
+// -----------------------------------------------------
+
+func (s *nullAtlas) SetPixelActiveCount(count int) {
+	s.indicesCount = count
+}
+
+func (s *nullAtlas) SetPointSize(size float32) {
+	s.pointSize = size
+}
+
+// -----------------------------------------------------
+// ISingleTextureAtlasX
+// -----------------------------------------------------
+
+func (s *nullAtlas) SelectCoordsByIndex(index int) {
+	s.coordIndex = index
+}
+
+func (s *nullAtlas) SpriteSheet() api.ISpriteSheet {
+	return s.spriteSheet
+}
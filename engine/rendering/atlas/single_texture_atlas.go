@@ -3,7 +3,6 @@ package atlas
 import (
 	"errors"
 	"image"
-	"path/filepath"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/wdevore/Ranger-Go-IGE/api"
@@ -11,6 +10,8 @@ import (
 )
 
 type singleTextureAtlas struct {
+	tint
+
 	world api.IWorld
 	burnt bool
 
@@ -26,6 +27,7 @@ type singleTextureAtlas struct {
 	spriteSheet api.ISpriteSheet
 
 	modelLoc, colorLoc int32
+	projLoc, viewLoc   int32
 }
 
 // ###################################################################
@@ -37,6 +39,14 @@ type singleTextureAtlas struct {
 // NewSingleTextureAtlas creates a specific texture atlas that renders to a single quad
 // This quad could represent a single character is a text string.
 func NewSingleTextureAtlas(atlasName string, spriteSheet api.ISpriteSheet, world api.IWorld) api.IAtlasX {
+	if isHeadless(world) {
+		return NewNullSingleTextureAtlas(atlasName, spriteSheet, world)
+	}
+
+	if isSoftware(world) {
+		return NewSoftwareSingleTextureAtlas(atlasName, spriteSheet, world)
+	}
+
 	o := new(singleTextureAtlas)
 
 	o.spriteSheet = spriteSheet
@@ -72,6 +82,10 @@ func (t *singleTextureAtlas) Configure() error {
 		return err
 	}
 
+	watchSpriteSheet(t.world, t.spriteSheet, func() {
+		t.bindTbo(t.spriteSheet.SheetImage())
+	})
+
 	return nil
 }
 
@@ -139,6 +153,7 @@ func (t *singleTextureAtlas) Bake() error {
 
 func (t *singleTextureAtlas) Use() {
 	t.shader.Use()
+	t.uploadSpaces()
 
 	gl.BindVertexArray(t.vao)
 
@@ -147,6 +162,16 @@ func (t *singleTextureAtlas) Use() {
 	gl.BindTexture(gl.TEXTURE_2D, t.tbo)
 }
 
+// uploadSpaces copies the World's projection and view matrices to
+// the shader. Both change when the display is resized.
+func (t *singleTextureAtlas) uploadSpaces() {
+	pm := t.world.Projection().Matrix()
+	gl.UniformMatrix4fv(t.projLoc, 1, false, &pm[0])
+
+	vm := t.world.Viewspace().Matrix()
+	gl.UniformMatrix4fv(t.viewLoc, 1, false, &vm[0])
+}
+
 func (t *singleTextureAtlas) UnUse() {
 	gl.BindVertexArray(0)
 }
@@ -158,7 +183,7 @@ func (t *singleTextureAtlas) Render(id int, model api.IMatrix4) {
 
 // SetColor sets the mix color on texture.
 func (t *singleTextureAtlas) SetColor(color []float32) {
-	gl.Uniform4fv(t.colorLoc, 1, &color[0])
+	gl.Uniform4fv(t.colorLoc, 1, &t.apply(color)[0])
 }
 
 // SelectCoordsByIndex implements: ISingleTextureAtlasX
@@ -186,19 +211,16 @@ func (t *singleTextureAtlas) SelectCoordsByIndex(index int) {
 }
 
 func (t *singleTextureAtlas) configureShaders(world api.IWorld) error {
-	dataPath, err := filepath.Abs(world.RelativePath())
-	if err != nil {
-		return err
-	}
-
 	shaders := world.Properties().Shaders
 
 	t.shader = rendering.NewShader(shaders.TextureVertexShaderFile, shaders.TextureFragmentShaderFile)
-	err = t.shader.Load(dataPath)
+	err := t.shader.Load(world.FS())
 	if err != nil {
 		return err
 	}
 
+	watchShader(world, t.shader, t.configureUniforms)
+
 	return nil
 }
 
@@ -218,21 +240,17 @@ func (t *singleTextureAtlas) configureUniforms() error {
 	}
 
 	// Projection and View
-	projLoc := gl.GetUniformLocation(program, gl.Str("projection\x00"))
-	if projLoc < 0 {
+	t.projLoc = gl.GetUniformLocation(program, gl.Str("projection\x00"))
+	if t.projLoc < 0 {
 		return errors.New("SingleTextureAtlas: couldn't find 'projection' uniform variable")
 	}
 
-	viewLoc := gl.GetUniformLocation(program, gl.Str("view\x00"))
-	if viewLoc < 0 {
+	t.viewLoc = gl.GetUniformLocation(program, gl.Str("view\x00"))
+	if t.viewLoc < 0 {
 		return errors.New("SingleTextureAtlas: couldn't find 'view' uniform variable")
 	}
 
-	pm := t.world.Projection().Matrix()
-	gl.UniformMatrix4fv(projLoc, 1, false, &pm[0])
-
-	vm := t.world.Viewspace().Matrix()
-	gl.UniformMatrix4fv(viewLoc, 1, false, &vm[0])
+	t.uploadSpaces()
 
 	return nil
 }
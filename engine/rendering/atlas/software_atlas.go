@@ -0,0 +1,110 @@
+package atlas
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/raster"
+)
+
+// The software atlas is used by the Software backend. It keeps the
+// same bookkeeping as the null atlas but renders through the World's
+// rasterizer instead of GL.
+
+const (
+	softwareMono = iota
+	softwarePixel
+	softwareTexture
+)
+
+// The single texture quad is drawn as two CCW triangles.
+var quadIndices = []uint32{0, 1, 2, 0, 2, 3}
+
+type softwareAtlas struct {
+	*nullAtlas
+
+	kind int
+
+	// x,y,z,s,t for the single texture quad
+	quad []float32
+}
+
+func newSoftwareAtlas(world api.IWorld, kind int) *softwareAtlas {
+	o := new(softwareAtlas)
+	o.nullAtlas = NewNullAtlas(world).(*nullAtlas)
+	o.kind = kind
+	return o
+}
+
+// NewSoftwareMonoAtlas creates a software version of a static or
+// dynamic mono atlas.
+func NewSoftwareMonoAtlas(world api.IWorld) api.IAtlasX {
+	return newSoftwareAtlas(world, softwareMono)
+}
+
+// NewSoftwarePixelAtlas creates a software version of a DynamicPixelAtlas.
+func NewSoftwarePixelAtlas(world api.IWorld) api.IAtlasX {
+	return newSoftwareAtlas(world, softwarePixel)
+}
+
+// NewSoftwareSingleTextureAtlas creates a software version of a SingleTextureAtlas.
+func NewSoftwareSingleTextureAtlas(atlasName string, spriteSheet api.ISpriteSheet, world api.IWorld) api.IAtlasX {
+	o := newSoftwareAtlas(world, softwareTexture)
+	o.atlasName = atlasName
+	o.spriteSheet = spriteSheet
+
+	return o
+}
+
+func (s *softwareAtlas) Burn() error {
+	err := s.nullAtlas.Burn()
+	if err != nil {
+		return err
+	}
+
+	// Like the GL atlas the sheet is watched once burnt. The rasterizer
+	// reads the sheet's image on every draw so there is nothing to upload.
+	if s.kind == softwareTexture {
+		watchSpriteSheet(s.world, s.spriteSheet, nil)
+	}
+
+	return nil
+}
+
+func isSoftware(world api.IWorld) bool {
+	return world.Properties().Engine.Backend == api.BackendSoftware
+}
+
+func (s *softwareAtlas) Render(id int, model api.IMatrix4) {
+	rasterizer := s.world.Rasterizer()
+	if rasterizer == nil {
+		return
+	}
+
+	switch s.kind {
+	case softwareMono:
+		shape := s.shapes[id]
+		rasterizer.DrawElements(int(shape.primitiveMode), shape.vertices, shape.indices, shape.indicesCount, model, s.color)
+	case softwarePixel:
+		rasterizer.SetPointSize(s.pointSize)
+		rasterizer.DrawElements(raster.Points, s.vertices, s.indices, s.indicesCount, model, s.color)
+	case softwareTexture:
+		rasterizer.DrawTexturedTriangles(s.texturedQuad(), quadIndices, s.spriteSheet.SheetImage(), model, s.color)
+	}
+}
+
+// texturedQuad builds the same unit quad as the GL single texture atlas
+// using the currently selected sub texture coords.
+func (s *softwareAtlas) texturedQuad() []float32 {
+	coords := s.spriteSheet.TextureSTCoordsByIndex(s.coordIndex)
+	if coords == nil {
+		panic("Sub texture not found")
+	}
+	c := *coords
+
+	s.quad = append(s.quad[:0],
+		-0.5, -0.5, 0.0, c[0], c[1],
+		0.5, -0.5, 0.0, c[2], c[3],
+		0.5, 0.5, 0.0, c[4], c[5],
+		-0.5, 0.5, 0.0, c[6], c[7])
+
+	return s.quad
+}
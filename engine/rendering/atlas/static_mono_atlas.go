@@ -2,7 +2,6 @@ package atlas
 
 import (
 	"errors"
-	"path/filepath"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 
@@ -14,6 +13,8 @@ import (
 // A mono Atlas uses a single color for the whole shape verse per vertex.
 
 type staticMonoAtlas struct {
+	tint
+
 	world api.IWorld
 	burnt bool
 
@@ -34,11 +35,21 @@ type staticMonoAtlas struct {
 
 	modelLoc int32
 	colorLoc int32
+	projLoc  int32
+	viewLoc  int32
 }
 
 // NewStaticMonoAtlas create atlas that holds static shapes
 // have a single (i.e. mono) color.
 func NewStaticMonoAtlas(world api.IWorld) api.IAtlasX {
+	if isHeadless(world) {
+		return NewNullAtlas(world)
+	}
+
+	if isSoftware(world) {
+		return NewSoftwareMonoAtlas(world)
+	}
+
 	o := new(staticMonoAtlas)
 	o.shapes = make(map[int]*shape)
 	o.world = world
@@ -203,9 +214,20 @@ func (s *staticMonoAtlas) Bake() error {
 
 func (s *staticMonoAtlas) Use() {
 	s.shader.Use()
+	s.uploadSpaces()
 	gl.BindVertexArray(s.vaoID)
 }
 
+// uploadSpaces copies the World's projection and view matrices to
+// the shader. Both change when the display is resized.
+func (s *staticMonoAtlas) uploadSpaces() {
+	pm := s.world.Projection().Matrix()
+	gl.UniformMatrix4fv(s.projLoc, 1, false, &pm[0])
+
+	vm := s.world.Viewspace().Matrix()
+	gl.UniformMatrix4fv(s.viewLoc, 1, false, &vm[0])
+}
+
 func (s *staticMonoAtlas) UnUse() {
 	// See opengl wiki as to why "glBindVertexArray(0)" isn't really necessary here:
 	// https://www.opengl.org/wiki/Vertex_Specification#Vertex_Buffer_Object
@@ -215,7 +237,7 @@ func (s *staticMonoAtlas) UnUse() {
 
 // SetColor sets the shader's color
 func (s *staticMonoAtlas) SetColor(color []float32) {
-	gl.Uniform4fv(s.colorLoc, 1, &color[0])
+	gl.Uniform4fv(s.colorLoc, 1, &s.apply(color)[0])
 }
 
 func (s *staticMonoAtlas) Render(id int, model api.IMatrix4) {
@@ -227,19 +249,16 @@ func (s *staticMonoAtlas) Render(id int, model api.IMatrix4) {
 }
 
 func (s *staticMonoAtlas) configureShaders(world api.IWorld) error {
-	dataPath, err := filepath.Abs(world.RelativePath())
-	if err != nil {
-		return err
-	}
-
 	shaders := world.Properties().Shaders
 
 	s.shader = rendering.NewShader(shaders.MonoVertexShaderFile, shaders.MonoFragmentShaderFile)
-	err = s.shader.Load(dataPath)
+	err := s.shader.Load(world.FS())
 	if err != nil {
 		return err
 	}
 
+	watchShader(world, s.shader, s.configureUniforms)
+
 	return nil
 }
 
@@ -259,21 +278,17 @@ func (s *staticMonoAtlas) configureUniforms() error {
 	}
 
 	// Projection and View
-	projLoc := gl.GetUniformLocation(program, gl.Str("projection\x00"))
-	if projLoc < 0 {
+	s.projLoc = gl.GetUniformLocation(program, gl.Str("projection\x00"))
+	if s.projLoc < 0 {
 		return errors.New("StaticMonoAtlas: couldn't find 'projection' uniform variable")
 	}
 
-	viewLoc := gl.GetUniformLocation(program, gl.Str("view\x00"))
-	if viewLoc < 0 {
+	s.viewLoc = gl.GetUniformLocation(program, gl.Str("view\x00"))
+	if s.viewLoc < 0 {
 		return errors.New("StaticMonoAtlas: couldn't find 'view' uniform variable")
 	}
 
-	pm := s.world.Projection().Matrix()
-	gl.UniformMatrix4fv(projLoc, 1, false, &pm[0])
-
-	vm := s.world.Viewspace().Matrix()
-	gl.UniformMatrix4fv(viewLoc, 1, false, &vm[0])
+	s.uploadSpaces()
 
 	return nil
 }
@@ -81,7 +81,7 @@ func NewPaletteInt64RGBAlpha(c uint64) api.IPalette {
 }
 
 func (p *palette) Components() (r, g, b, a float32) {
-	return r, g, b, a
+	return p.r, p.g, p.b, p.a
 }
 
 func (p *palette) Array() []float32 {
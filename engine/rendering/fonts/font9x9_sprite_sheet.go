@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
-	"io/ioutil"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
@@ -39,6 +40,10 @@ type font9x9Sheet struct {
 	manifest      string
 	width, height int64
 
+	fsys    fs.FS
+	dir     string
+	flipped bool
+
 	sheet *image.NRGBA
 
 	manifestJ images.TextureManifestJSON
@@ -59,32 +64,54 @@ func (t *font9x9Sheet) Load(relativePath string, flipped bool) {
 		panic(err)
 	}
 
-	eConfFile, err := os.Open(dataPath + "/" + t.manifest)
+	err = t.LoadFS(os.DirFS(dataPath), ".", flipped)
 	if err != nil {
 		panic(err)
 	}
+}
 
-	defer eConfFile.Close()
+// LoadFS loads the manifest, within dir, from a file system
+func (t *font9x9Sheet) LoadFS(fsys fs.FS, dir string, flipped bool) error {
+	t.fsys = fsys
+	t.dir = dir
+	t.flipped = flipped
+
+	return t.Reload()
+}
 
-	bytes, err := ioutil.ReadAll(eConfFile)
+// Reload reads the manifest and image again
+func (t *font9x9Sheet) Reload() error {
+	bytes, err := fs.ReadFile(t.fsys, path.Join(t.dir, t.manifest))
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	err = json.Unmarshal(bytes, &t.manifestJ)
+	var manifestJ images.TextureManifestJSON
+	err = json.Unmarshal(bytes, &manifestJ)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	file := dataPath + "/" + t.manifestJ.OutputPNG
+	file := path.Join(t.dir, manifestJ.OutputPNG)
 	fmt.Println("TextureAtlas.Load loading: ", file)
-	image, err := t.loadImage(file, flipped)
+	image, err := t.loadImage(file, t.flipped)
 
 	if err != nil {
-		panic(err)
+		return err
 	}
 
+	t.manifestJ = manifestJ
 	t.sheet = image
+
+	return nil
+}
+
+// Files returns the manifest and image files
+func (t *font9x9Sheet) Files() (fs.FS, []string) {
+	return t.fsys, []string{
+		path.Join(t.dir, t.manifest),
+		path.Join(t.dir, t.manifestJ.OutputPNG),
+	}
 }
 
 // AtlasImage returns image atlas
@@ -134,13 +161,15 @@ func (t *font9x9Sheet) TextureSTCoordsByIndex(idx int) *[]float32 {
 	return &t.manifestJ.Tiles[idx].STCoords
 }
 
-func (t *font9x9Sheet) loadImage(path string, flipped bool) (*image.NRGBA, error) {
-	file, err := os.Open(path)
+func (t *font9x9Sheet) loadImage(file string, flipped bool) (*image.NRGBA, error) {
+	f, err := t.fsys.Open(file)
 	if err != nil {
 		return nil, err
 	}
 
-	img, _, err := image.Decode(file)
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,74 @@
+package fonts
+
+import (
+	// Sheets loaded by the asset manager are png images.
+	_ "image/png"
+	"io/fs"
+	"path"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+type spriteSheetLoader struct {
+	fsys    fs.FS
+	flipped bool
+}
+
+// NewSpriteSheetLoader creates an asset loader for sprite sheets. The
+// source is the path, within fsys, to the manifest. The manifest's
+// image is expected in the same directory.
+func NewSpriteSheetLoader(fsys fs.FS, flipped bool) api.IAssetLoader {
+	o := new(spriteSheetLoader)
+	o.fsys = fsys
+	o.flipped = flipped
+	return o
+}
+
+func (l *spriteSheetLoader) Decode(name, source string) (interface{}, error) {
+	sheet := NewFont9x9SpriteSheet(name, path.Base(source))
+
+	err := sheet.LoadFS(l.fsys, path.Dir(source), l.flipped)
+	if err != nil {
+		return nil, err
+	}
+
+	return sheet, nil
+}
+
+// Upload does nothing. The image is uploaded by the atlas using it.
+func (l *spriteSheetLoader) Upload(asset interface{}) error {
+	return nil
+}
+
+func (l *spriteSheetLoader) Release(asset interface{}) {
+}
+
+type rasterFontLoader struct {
+	fsys fs.FS
+}
+
+// NewRasterFontLoader creates an asset loader for raster fonts. The
+// source is a data file in fsys's /assets directory.
+func NewRasterFontLoader(fsys fs.FS) api.IAssetLoader {
+	o := new(rasterFontLoader)
+	o.fsys = fsys
+	return o
+}
+
+func (l *rasterFontLoader) Decode(name, source string) (interface{}, error) {
+	font := NewRasterFont()
+
+	err := font.InitializeFS(l.fsys, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return font, nil
+}
+
+func (l *rasterFontLoader) Upload(asset interface{}) error {
+	return nil
+}
+
+func (l *rasterFontLoader) Release(asset interface{}) {
+}
@@ -3,7 +3,9 @@ package fonts
 import (
 	"bufio"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -34,15 +36,19 @@ func (r *rasterFont) Initialize(dataFile string, relativePath string) error {
 		return err
 	}
 
-	fontFile := dataPath + "/assets/" + dataFile
-	file, err := os.Open(fontFile)
-	defer file.Close()
+	return r.InitializeFS(os.DirFS(dataPath), dataFile)
+}
 
+func (r *rasterFont) InitializeFS(fsys fs.FS, dataFile string) error {
+	fontFile := path.Join(api.AssetDir, dataFile)
+	file, err := fsys.Open(fontFile)
 	if err != nil {
 		fmt.Println("RasterFont: Opening ", fontFile)
 		return err
 	}
 
+	defer file.Close()
+
 	scanner := bufio.NewScanner(file)
 	scanner.Split(bufio.ScanLines)
 	fmt.Println("Opened raster font file")
@@ -0,0 +1,51 @@
+package rendering
+
+import (
+	"io/fs"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// NullShader is a shader that never compiles anything. It is used by
+// the headless atlases.
+type NullShader struct {
+	vertexSrc   string
+	fragmentSrc string
+}
+
+// NewNullShader creates a no-op shader
+func NewNullShader(vertexSrc, fragmentSrc string) api.IShader {
+	s := new(NullShader)
+	s.vertexSrc = vertexSrc
+	s.fragmentSrc = fragmentSrc
+	return s
+}
+
+// Load does nothing
+func (s *NullShader) Load(fsys fs.FS) error {
+	return nil
+}
+
+// Reload does nothing
+func (s *NullShader) Reload(fsys fs.FS) error {
+	return nil
+}
+
+// Sources returns the vertex and fragment file names
+func (s *NullShader) Sources() (vertexSrc, fragmentSrc string) {
+	return s.vertexSrc, s.fragmentSrc
+}
+
+// Compile does nothing
+func (s *NullShader) Compile() error {
+	return nil
+}
+
+// Use does nothing
+func (s *NullShader) Use() {
+}
+
+// Program always returns 0 which is never a valid GL program.
+func (s *NullShader) Program() uint32 {
+	return 0
+}
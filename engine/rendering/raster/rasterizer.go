@@ -0,0 +1,423 @@
+package raster
+
+import (
+	"image"
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
+)
+
+// Primitive modes. The values match their GL counterparts so shapes
+// built with gl.TRIANGLES, gl.LINE_LOOP etc. can be handed straight
+// to the rasterizer.
+const (
+	Points        = 0x0000
+	Lines         = 0x0001
+	LineLoop      = 0x0002
+	LineStrip     = 0x0003
+	Triangles     = 0x0004
+	TriangleStrip = 0x0005
+	TriangleFan   = 0x0006
+)
+
+const (
+	xyzStride   = 3
+	xyzstStride = 5
+)
+
+// vertex is a vertex in image space (y down) with texture coords.
+type vertex struct {
+	x, y float64
+	s, t float64
+}
+
+type rasterizer struct {
+	world api.IWorld
+
+	image *image.NRGBA
+
+	pointSize float64
+
+	pv  api.IMatrix4
+	mvp api.IMatrix4
+
+	// The World's viewport in image space. Nothing is drawn outside
+	// the clip which is the viewport limited to the image.
+	view image.Rectangle
+	clip image.Rectangle
+
+	// Fragment color for the current draw
+	r, g, b, a float32
+	texture    *image.NRGBA
+}
+
+// NewRasterizer creates a rasterizer with an image of width x height.
+// The World's projection and view matrices are read on every draw.
+func NewRasterizer(width, height int, world api.IWorld) api.IRasterizer {
+	o := new(rasterizer)
+	o.world = world
+	o.image = image.NewNRGBA(image.Rect(0, 0, width, height))
+	o.pointSize = 1.0
+	o.pv = maths.NewMatrix4()
+	o.mvp = maths.NewMatrix4()
+	return o
+}
+
+func (r *rasterizer) Image() *image.NRGBA {
+	return r.image
+}
+
+func (r *rasterizer) Resize(width, height int) {
+	r.image = image.NewNRGBA(image.Rect(0, 0, width, height))
+}
+
+// Clear fills the whole image without blending.
+func (r *rasterizer) Clear(rc, gc, bc, ac float32) {
+	c := [4]uint8{toByte(rc), toByte(gc), toByte(bc), toByte(ac)}
+	pix := r.image.Pix
+	for i := 0; i < len(pix); i += 4 {
+		copy(pix[i:i+4], c[:])
+	}
+}
+
+func (r *rasterizer) SetPointSize(size float32) {
+	r.pointSize = float64(size)
+}
+
+func (r *rasterizer) DrawElements(mode int, vertices []float32, indices []uint32, count int, model api.IMatrix4, color []float32) {
+	if count > len(indices) {
+		count = len(indices)
+	}
+
+	r.begin(model, color, nil)
+
+	v := func(i int) vertex {
+		return r.project(vertices, xyzStride, indices[i])
+	}
+
+	switch mode {
+	case Points:
+		for i := 0; i < count; i++ {
+			r.point(v(i))
+		}
+	case Lines:
+		for i := 0; i+1 < count; i += 2 {
+			r.line(v(i), v(i+1))
+		}
+	case LineStrip, LineLoop:
+		for i := 0; i+1 < count; i++ {
+			r.line(v(i), v(i+1))
+		}
+		if mode == LineLoop && count > 2 {
+			r.line(v(count-1), v(0))
+		}
+	case Triangles:
+		for i := 0; i+2 < count; i += 3 {
+			r.triangle(v(i), v(i+1), v(i+2))
+		}
+	case TriangleStrip:
+		for i := 0; i+2 < count; i++ {
+			r.triangle(v(i), v(i+1), v(i+2))
+		}
+	case TriangleFan:
+		for i := 1; i+1 < count; i++ {
+			r.triangle(v(0), v(i), v(i+1))
+		}
+	}
+}
+
+func (r *rasterizer) DrawTexturedTriangles(vertices []float32, indices []uint32, texture *image.NRGBA, model api.IMatrix4, color []float32) {
+	r.begin(model, color, texture)
+
+	for i := 0; i+2 < len(indices); i += 3 {
+		r.triangle(
+			r.project(vertices, xyzstStride, indices[i]),
+			r.project(vertices, xyzstStride, indices[i+1]),
+			r.project(vertices, xyzstStride, indices[i+2]))
+	}
+}
+
+// begin captures the draw state: mvp = projection * view * model
+func (r *rasterizer) begin(model api.IMatrix4, color []float32, texture *image.NRGBA) {
+	maths.Multiply4(r.world.Projection(), r.world.Viewspace(), r.pv)
+	maths.Multiply4(r.pv, model, r.mvp)
+
+	// The viewport's origin is bottom-left where as the image's is top-left.
+	x, y, width, height := r.world.Viewport().Dimensions()
+	imageHeight := r.image.Rect.Dy()
+	r.view = image.Rect(x, imageHeight-(y+height), x+width, imageHeight-y)
+	r.clip = r.view.Intersect(r.image.Rect)
+
+	r.r, r.g, r.b, r.a = 1.0, 1.0, 1.0, 1.0
+	if len(color) >= 4 {
+		r.r, r.g, r.b, r.a = color[0], color[1], color[2], color[3]
+	}
+
+	r.texture = texture
+}
+
+// project maps a vertex through the mvp to the viewport in image space.
+// GL's NDC has y up where as the image has y down.
+func (r *rasterizer) project(vertices []float32, stride int, index uint32) vertex {
+	i := int(index) * stride
+	x, y, z := vertices[i], vertices[i+1], vertices[i+2]
+
+	m := r.mvp.Matrix()
+	cx := m[maths.M00]*x + m[maths.M01]*y + m[maths.M02]*z + m[maths.M03]
+	cy := m[maths.M10]*x + m[maths.M11]*y + m[maths.M12]*z + m[maths.M13]
+	cw := m[maths.M30]*x + m[maths.M31]*y + m[maths.M32]*z + m[maths.M33]
+
+	if cw == 0 {
+		cw = 1
+	}
+
+	view := r.view
+	w := float64(view.Dx())
+	h := float64(view.Dy())
+
+	v := vertex{
+		x: float64(view.Min.X) + (float64(cx/cw)+1.0)*0.5*w,
+		y: float64(view.Min.Y) + (1.0-float64(cy/cw))*0.5*h,
+	}
+
+	if stride == xyzstStride {
+		v.s = float64(vertices[i+3])
+		v.t = float64(vertices[i+4])
+	}
+
+	return v
+}
+
+// edge is positive when p is to the right of a->b in image space.
+func edge(a, b vertex, px, py float64) float64 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+// topLeft implements the top-left fill rule so pixels on an edge
+// shared by two triangles are only drawn once.
+func topLeft(a, b vertex) bool {
+	dx := b.x - a.x
+	dy := b.y - a.y
+	return (dy == 0 && dx > 0) || dy < 0
+}
+
+func (r *rasterizer) triangle(a, b, c vertex) {
+	if !finite(a) || !finite(b) || !finite(c) {
+		return
+	}
+
+	area := edge(a, b, c.x, c.y)
+	if area == 0 {
+		return
+	}
+	// Normalize the winding so the edge functions are positive inside.
+	if area < 0 {
+		b, c = c, b
+		area = -area
+	}
+
+	bounds := r.clip
+	minX := int(math.Max(math.Floor(math.Min(a.x, math.Min(b.x, c.x))), float64(bounds.Min.X)))
+	maxX := int(math.Min(math.Ceil(math.Max(a.x, math.Max(b.x, c.x))), float64(bounds.Max.X-1)))
+	minY := int(math.Max(math.Floor(math.Min(a.y, math.Min(b.y, c.y))), float64(bounds.Min.Y)))
+	maxY := int(math.Min(math.Ceil(math.Max(a.y, math.Max(b.y, c.y))), float64(bounds.Max.Y-1)))
+
+	tlA := topLeft(b, c)
+	tlB := topLeft(c, a)
+	tlC := topLeft(a, b)
+
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+
+			wa := edge(b, c, px, py)
+			wb := edge(c, a, px, py)
+			wc := edge(a, b, px, py)
+
+			if !inside(wa, tlA) || !inside(wb, tlB) || !inside(wc, tlC) {
+				continue
+			}
+
+			if r.texture == nil {
+				r.blend(x, y, r.r, r.g, r.b, r.a)
+				continue
+			}
+
+			s := (wa*a.s + wb*b.s + wc*c.s) / area
+			t := (wa*a.t + wb*b.t + wc*c.t) / area
+			tr, tg, tb, ta := r.sample(s, t)
+			r.blend(x, y, tr*r.r, tg*r.g, tb*r.b, ta*r.a)
+		}
+	}
+}
+
+func inside(w float64, topLeft bool) bool {
+	return w > 0 || (w == 0 && topLeft)
+}
+
+// sample is a nearest texel lookup. Like GL, t = 0 is the first row.
+func (r *rasterizer) sample(s, t float64) (float32, float32, float32, float32) {
+	tex := r.texture
+	w := tex.Rect.Dx()
+	h := tex.Rect.Dy()
+
+	x := clamp(int(s*float64(w)), 0, w-1)
+	y := clamp(int(t*float64(h)), 0, h-1)
+
+	c := tex.NRGBAAt(tex.Rect.Min.X+x, tex.Rect.Min.Y+y)
+	return float32(c.R) / 255.0, float32(c.G) / 255.0, float32(c.B) / 255.0, float32(c.A) / 255.0
+}
+
+// line is a Bresenham line between pixel containing a and b. The
+// segment is clipped first so only visible pixels are stepped over.
+func (r *rasterizer) line(a, b vertex) {
+	a, b, visible := r.clipLine(a, b)
+	if !visible {
+		return
+	}
+
+	x0, y0 := int(math.Floor(a.x)), int(math.Floor(a.y))
+	x1, y1 := int(math.Floor(b.x)), int(math.Floor(b.y))
+
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+
+	for {
+		r.blend(x0, y0, r.r, r.g, r.b, r.a)
+		if x0 == x1 && y0 == y1 {
+			break
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// clipLine clips a->b to the clip rectangle, Liang-Barsky style. It
+// returns false if nothing is left or an end isn't a finite number.
+func (r *rasterizer) clipLine(a, b vertex) (vertex, vertex, bool) {
+	if !finite(a) || !finite(b) {
+		return a, b, false
+	}
+
+	dx := b.x - a.x
+	dy := b.y - a.y
+
+	// Each edge as p*t <= q
+	edges := [4][2]float64{
+		{-dx, a.x - float64(r.clip.Min.X)},
+		{dx, float64(r.clip.Max.X) - a.x},
+		{-dy, a.y - float64(r.clip.Min.Y)},
+		{dy, float64(r.clip.Max.Y) - a.y},
+	}
+
+	t0, t1 := 0.0, 1.0
+	for _, e := range edges {
+		p, q := e[0], e[1]
+		if p == 0 {
+			// Parallel to the edge
+			if q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+
+		t := q / p
+		if p < 0 {
+			if t > t1 {
+				return a, b, false
+			}
+			t0 = math.Max(t0, t)
+		} else {
+			if t < t0 {
+				return a, b, false
+			}
+			t1 = math.Min(t1, t)
+		}
+	}
+
+	clippedA := vertex{x: a.x + t0*dx, y: a.y + t0*dy}
+	clippedB := vertex{x: a.x + t1*dx, y: a.y + t1*dy}
+
+	return clippedA, clippedB, true
+}
+
+func finite(v vertex) bool {
+	return !math.IsNaN(v.x) && !math.IsInf(v.x, 0) && !math.IsNaN(v.y) && !math.IsInf(v.y, 0)
+}
+
+// point covers the pixels whose centers are inside a square of
+// pointSize centered on the vertex.
+func (r *rasterizer) point(v vertex) {
+	half := r.pointSize / 2.0
+	x0 := int(math.Ceil(v.x - half - 0.5))
+	x1 := int(math.Ceil(v.x+half-0.5)) - 1
+	y0 := int(math.Ceil(v.y - half - 0.5))
+	y1 := int(math.Ceil(v.y+half-0.5)) - 1
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			r.blend(x, y, r.r, r.g, r.b, r.a)
+		}
+	}
+}
+
+// blend matches the display's GL blend function:
+// SRC_ALPHA, ONE_MINUS_SRC_ALPHA
+func (r *rasterizer) blend(x, y int, sr, sg, sb, sa float32) {
+	if !(image.Point{x, y}.In(r.clip)) {
+		return
+	}
+
+	i := r.image.PixOffset(x, y)
+	p := r.image.Pix[i : i+4 : i+4]
+
+	inv := 1.0 - sa
+	p[0] = toByte(sr*sa + float32(p[0])/255.0*inv)
+	p[1] = toByte(sg*sa + float32(p[1])/255.0*inv)
+	p[2] = toByte(sb*sa + float32(p[2])/255.0*inv)
+	p[3] = toByte(sa*sa + float32(p[3])/255.0*inv)
+}
+
+func toByte(v float32) uint8 {
+	if v <= 0.0 {
+		return 0
+	}
+	if v >= 1.0 {
+		return 255
+	}
+	return uint8(v*255.0 + 0.5)
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -3,7 +3,8 @@ package rendering
 
 import (
 	"fmt"
-	"io/ioutil"
+	"io/fs"
+	"path"
 	"strings"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
@@ -39,10 +40,10 @@ func NewShader(vertexSrc, fragmentSrc string) api.IShader {
 }
 
 // Load reads and compiles shader programs
-func (s *Shader) Load(relativePath string) error {
+func (s *Shader) Load(fsys fs.FS) error {
 
 	var err error
-	s.vertexCode, s.fragmentCode, err = fetch(relativePath, s.vertexSrc, s.fragmentSrc)
+	s.vertexCode, s.fragmentCode, err = fetch(fsys, s.vertexSrc, s.fragmentSrc)
 	if err != nil {
 		return err
 	}
@@ -50,6 +51,33 @@ func (s *Shader) Load(relativePath string) error {
 	return s.Compile()
 }
 
+// Reload reads and compiles the shader programs again. The current
+// program is only replaced, and deleted, if the new one compiles.
+func (s *Shader) Reload(fsys fs.FS) error {
+	vertexCode, fragmentCode, err := fetch(fsys, s.vertexSrc, s.fragmentSrc)
+	if err != nil {
+		return err
+	}
+
+	program, err := newProgram(vertexCode, fragmentCode)
+	if err != nil {
+		return err
+	}
+
+	gl.DeleteProgram(s.program)
+
+	s.vertexCode = vertexCode
+	s.fragmentCode = fragmentCode
+	s.program = program
+
+	return nil
+}
+
+// Sources returns the vertex and fragment file names
+func (s *Shader) Sources() (vertexSrc, fragmentSrc string) {
+	return s.vertexSrc, s.fragmentSrc
+}
+
 // Compile compiles shader programs
 func (s *Shader) Compile() error {
 	// fmt.Println("Shader: compiling...")
@@ -74,13 +102,13 @@ func (s *Shader) Program() uint32 {
 	return s.program
 }
 
-func fetch(relativePath string, vertexSrc, fragmentSrc string) (vCode, fCode string, err error) {
+func fetch(fsys fs.FS, vertexSrc, fragmentSrc string) (vCode, fCode string, err error) {
 
 	// Vertex source -----------------------------------------------
-	filePath := fmt.Sprintf(relativePath+api.RelativeShaderPath+"%s", vertexSrc)
+	filePath := path.Join(api.ShaderDir, vertexSrc)
 
 	var bytes []byte
-	bytes, err = ioutil.ReadFile(filePath)
+	bytes, err = fs.ReadFile(fsys, filePath)
 
 	if err != nil {
 		return "", "", err
@@ -90,9 +118,9 @@ func fetch(relativePath string, vertexSrc, fragmentSrc string) (vCode, fCode str
 	vCode = string(bytes)
 
 	// Fragment source -----------------------------------------------
-	filePath = fmt.Sprintf(relativePath+api.RelativeShaderPath+"%s", fragmentSrc)
+	filePath = path.Join(api.ShaderDir, fragmentSrc)
 
-	bytes, err = ioutil.ReadFile(filePath)
+	bytes, err = fs.ReadFile(fsys, filePath)
 
 	if err != nil {
 		return "", "", err
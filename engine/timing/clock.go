@@ -0,0 +1,45 @@
+// Package timing provides clocks and other timing helpers for the engine.
+package timing
+
+import (
+	"time"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+type wallClock struct {
+}
+
+// NewWallClock creates a clock that reads the OS wall time. This is the
+// engine's default clock.
+func NewWallClock() api.IClock {
+	o := new(wallClock)
+	return o
+}
+
+func (c *wallClock) Now() int64 {
+	return time.Now().UnixNano()
+}
+
+type manualClock struct {
+	now int64
+}
+
+// NewManualClock creates a clock that starts at zero and only advances
+// when Advance or Set is called.
+func NewManualClock() api.IManualClock {
+	o := new(manualClock)
+	return o
+}
+
+func (c *manualClock) Now() int64 {
+	return c.now
+}
+
+func (c *manualClock) Advance(nanoseconds int64) {
+	c.now += nanoseconds
+}
+
+func (c *manualClock) Set(nanoseconds int64) {
+	c.now = nanoseconds
+}
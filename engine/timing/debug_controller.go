@@ -0,0 +1,84 @@
+package timing
+
+import (
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+const (
+	minTimeScale = 1.0 / 16.0
+	maxTimeScale = 16.0
+)
+
+type debugController struct {
+	paused      bool
+	stepPending bool
+	timeScale   float64
+}
+
+// NewDebugController creates a controller for pausing, single stepping
+// and time scaling the update loop.
+func NewDebugController() api.IDebugController {
+	o := new(debugController)
+	o.timeScale = 1.0
+	return o
+}
+
+func (d *debugController) Paused() bool {
+	return d.paused
+}
+
+func (d *debugController) SetPaused(paused bool) {
+	d.paused = paused
+	if !paused {
+		d.stepPending = false
+	}
+}
+
+func (d *debugController) TogglePause() {
+	d.SetPaused(!d.paused)
+	fmt.Println("Debug: paused ", d.paused)
+}
+
+// RequestStep is ignored unless the controller is paused.
+func (d *debugController) RequestStep() {
+	if d.paused {
+		d.stepPending = true
+	}
+}
+
+func (d *debugController) TakeStep() bool {
+	step := d.stepPending
+	d.stepPending = false
+	return step
+}
+
+func (d *debugController) TimeScale() float64 {
+	return d.timeScale
+}
+
+// SetTimeScale sets the scale clamped to [1/16, 16]
+func (d *debugController) SetTimeScale(scale float64) {
+	if scale < minTimeScale {
+		scale = minTimeScale
+	} else if scale > maxTimeScale {
+		scale = maxTimeScale
+	}
+	d.timeScale = scale
+}
+
+func (d *debugController) ScaleUp() {
+	d.SetTimeScale(d.timeScale * 2.0)
+	fmt.Println("Debug: time scale ", d.timeScale)
+}
+
+func (d *debugController) ScaleDown() {
+	d.SetTimeScale(d.timeScale / 2.0)
+	fmt.Println("Debug: time scale ", d.timeScale)
+}
+
+func (d *debugController) ResetTimeScale() {
+	d.timeScale = 1.0
+	fmt.Println("Debug: time scale ", d.timeScale)
+}
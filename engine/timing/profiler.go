@@ -0,0 +1,279 @@
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// DefaultProfileFrames is the ring buffer size used when none is given.
+const DefaultProfileFrames = 300
+
+var phaseNames = [api.ProfilePhaseCount]string{"Poll", "Update", "Visit", "Swap"}
+
+type phaseSpan struct {
+	start    int64
+	duration int64
+	began    int64
+	active   bool
+}
+
+type drawStat struct {
+	calls    int
+	duration int64
+}
+
+type profileFrame struct {
+	index    uint64
+	start    int64
+	duration int64
+	phases   [api.ProfilePhaseCount]phaseSpan
+	draws    map[string]*drawStat
+}
+
+type profiler struct {
+	enabled bool
+	origin  time.Time
+
+	// Ring buffer. head is where the next frame is written.
+	frames []profileFrame
+	head   int
+	count  int
+
+	frameIndex uint64
+	current    *profileFrame
+}
+
+// NewProfiler creates a disabled profiler retaining up to capacity frames.
+func NewProfiler(capacity int) api.IProfiler {
+	o := new(profiler)
+	if capacity <= 0 {
+		capacity = DefaultProfileFrames
+	}
+	o.frames = make([]profileFrame, capacity)
+	for i := range o.frames {
+		o.frames[i].draws = make(map[string]*drawStat)
+	}
+	o.origin = time.Now()
+	return o
+}
+
+// now is nanoseconds since the profiler was created.
+func (p *profiler) now() int64 {
+	return int64(time.Since(p.origin))
+}
+
+func (p *profiler) Enabled() bool {
+	return p.enabled
+}
+
+func (p *profiler) SetEnabled(enabled bool) {
+	p.enabled = enabled
+	if !enabled {
+		p.current = nil
+	}
+}
+
+func (p *profiler) Capacity() int {
+	return len(p.frames)
+}
+
+func (p *profiler) Reset() {
+	p.head = 0
+	p.count = 0
+	p.current = nil
+}
+
+func (p *profiler) BeginFrame() {
+	if !p.enabled {
+		return
+	}
+
+	f := &p.frames[p.head]
+	f.index = p.frameIndex
+	f.start = p.now()
+	f.duration = 0
+	f.phases = [api.ProfilePhaseCount]phaseSpan{}
+	for k := range f.draws {
+		delete(f.draws, k)
+	}
+
+	p.current = f
+}
+
+func (p *profiler) EndFrame() {
+	if p.current == nil {
+		return
+	}
+
+	p.current.duration = p.now() - p.current.start
+	p.current = nil
+
+	p.frameIndex++
+	p.head = (p.head + 1) % len(p.frames)
+	if p.count < len(p.frames) {
+		p.count++
+	}
+}
+
+func (p *profiler) BeginPhase(phase int) {
+	if p.current == nil {
+		return
+	}
+
+	ps := &p.current.phases[phase]
+	ps.began = p.now()
+	if !ps.active {
+		ps.start = ps.began
+		ps.active = true
+	}
+}
+
+func (p *profiler) EndPhase(phase int) {
+	if p.current == nil {
+		return
+	}
+
+	ps := &p.current.phases[phase]
+	ps.duration += p.now() - ps.began
+}
+
+func (p *profiler) BeginDraw() int64 {
+	if p.current == nil {
+		return 0
+	}
+	return p.now()
+}
+
+func (p *profiler) EndDraw(nodeType string, mark int64) {
+	if p.current == nil {
+		return
+	}
+
+	ds, ok := p.current.draws[nodeType]
+	if !ok {
+		ds = new(drawStat)
+		p.current.draws[nodeType] = ds
+	}
+	ds.calls++
+	ds.duration += p.now() - mark
+}
+
+// frame maps a relative index (0 = oldest) into the ring buffer.
+func (p *profiler) frame(index int) *profileFrame {
+	if index < 0 || index >= p.count {
+		return nil
+	}
+	oldest := (p.head - p.count + len(p.frames)) % len(p.frames)
+	return &p.frames[(oldest+index)%len(p.frames)]
+}
+
+func (p *profiler) FrameCount() int {
+	return p.count
+}
+
+func (p *profiler) FrameDuration(index int) int64 {
+	f := p.frame(index)
+	if f == nil {
+		return 0
+	}
+	return f.duration
+}
+
+func (p *profiler) PhaseDuration(index int, phase int) int64 {
+	f := p.frame(index)
+	if f == nil {
+		return 0
+	}
+	return f.phases[phase].duration
+}
+
+func (p *profiler) DrawStats(index int, nodeType string) (calls int, duration int64) {
+	f := p.frame(index)
+	if f == nil {
+		return 0, 0
+	}
+	ds, ok := f.draws[nodeType]
+	if !ok {
+		return 0, 0
+	}
+	return ds.calls, ds.duration
+}
+
+// traceEvent is a single entry of the Chrome trace event format.
+// Timestamps and durations are in microseconds.
+type traceEvent struct {
+	Name string                 `json:"name"`
+	Cat  string                 `json:"cat,omitempty"`
+	Ph   string                 `json:"ph"`
+	Ts   float64                `json:"ts"`
+	Dur  float64                `json:"dur,omitempty"`
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+func toMicro(ns int64) float64 {
+	return float64(ns) / 1000.0
+}
+
+func (p *profiler) WriteChromeTrace(w io.Writer) error {
+	trace := traceFile{DisplayTimeUnit: "ms"}
+
+	for i := 0; i < p.count; i++ {
+		f := p.frame(i)
+
+		trace.TraceEvents = append(trace.TraceEvents, traceEvent{
+			Name: fmt.Sprintf("Frame %d", f.index),
+			Cat:  "frame",
+			Ph:   "X",
+			Ts:   toMicro(f.start),
+			Dur:  toMicro(f.duration),
+			Pid:  1, Tid: 1,
+			Args: map[string]interface{}{"frame": f.index},
+		})
+
+		for phase, ps := range f.phases {
+			if !ps.active {
+				continue
+			}
+			trace.TraceEvents = append(trace.TraceEvents, traceEvent{
+				Name: phaseNames[phase],
+				Cat:  "phase",
+				Ph:   "X",
+				Ts:   toMicro(ps.start),
+				Dur:  toMicro(ps.duration),
+				Pid:  1, Tid: 1,
+			})
+		}
+
+		if len(f.draws) == 0 {
+			continue
+		}
+
+		// Counter events show per node type totals for the frame.
+		calls := map[string]interface{}{}
+		times := map[string]interface{}{}
+		for t, ds := range f.draws {
+			calls[t] = ds.calls
+			times[t] = toMicro(ds.duration)
+		}
+
+		trace.TraceEvents = append(trace.TraceEvents,
+			traceEvent{Name: "Draw calls", Ph: "C", Ts: toMicro(f.start), Pid: 1, Tid: 1, Args: calls},
+			traceEvent{Name: "Draw time (us)", Ph: "C", Ts: toMicro(f.start), Pid: 1, Tid: 1, Args: times},
+		)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(trace)
+}
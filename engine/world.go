@@ -1,19 +1,24 @@
 package engine
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"path"
+	"time"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/assets"
+	"github.com/wdevore/Ranger-Go-IGE/engine/audio"
 	"github.com/wdevore/Ranger-Go-IGE/engine/configuration"
 	"github.com/wdevore/Ranger-Go-IGE/engine/display"
+	"github.com/wdevore/Ranger-Go-IGE/engine/input"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
 	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
 	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/engine/reload"
 	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/fonts"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/raster"
+	"github.com/wdevore/Ranger-Go-IGE/engine/timing"
 	"github.com/wdevore/Ranger-Go-IGE/extras"
 )
 
@@ -30,11 +35,20 @@ type world struct {
 
 	properties   *configuration.Properties
 	relativePath string
+	fsys         fs.FS
 
-	atlases map[string]api.IAtlasX
+	assets api.IAssetManager
 
 	rasterFont api.IRasterFont
 
+	input      api.IInputMap
+	inputState api.IInputState
+	gamepads   api.IGamepads
+	recorder   api.IInputRecorder
+	events     api.IEventQueue
+
+	viewport     *display.Viewport
+	camera       *display.Projection
 	projection   api.IMatrix4
 	viewSpace    api.IMatrix4
 	invViewSpace api.IMatrix4
@@ -43,52 +57,45 @@ type world struct {
 	fps        int
 	ups        int
 	renderTime float64
+
+	debugController api.IDebugController
+	profiler        api.IProfiler
+	hotReloader     api.IHotReloader
+	rasterizer      api.IRasterizer
 }
 
-func newWorld(relativePath string) api.IWorld {
+func newWorld(fsys fs.FS, relativePath string) (api.IWorld, error) {
 	o := new(world)
 
 	o.sceneGraph = nodes.NewNodeManager()
 
 	o.properties = &configuration.Properties{}
 	o.relativePath = relativePath
+	o.fsys = fsys
 
-	o.atlases = make(map[string]api.IAtlasX)
-
-	dataPath, err := filepath.Abs(relativePath)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
-
-	eConfFile, err := os.Open(dataPath + "/engine/configuration/config.json")
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
+	o.assets = assets.NewAssetManager()
+	o.assets.RegisterLoader(api.AssetSpriteSheet, fonts.NewSpriteSheetLoader(fsys, true))
+	o.assets.RegisterLoader(api.AssetRasterFont, fonts.NewRasterFontLoader(fsys))
+	o.assets.RegisterLoader(api.AssetSound, audio.NewSoundLoader(fsys))
 
-	defer eConfFile.Close()
+	o.input = input.NewInputMap()
+	o.inputState = input.NewInputState(o)
+	o.gamepads = input.NewGamepads(0.0)
+	o.recorder = input.NewInputRecorder()
+	o.events = io.NewEventQueue()
 
-	bytes, err := ioutil.ReadAll(eConfFile)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
+	o.debugController = timing.NewDebugController()
 
-	err = json.Unmarshal(bytes, o.properties)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
-
-	return o
-}
-
-func (w *world) loadShaderSource(dataPath, shaderSrc string) (code *string, err error) {
-	file, err := os.Open(dataPath + "/engine/assets/shaders/" + shaderSrc)
+	err := o.properties.LoadFS(fsys, api.ConfigFile)
 	if err != nil {
 		return nil, err
 	}
 
-	defer file.Close()
+	return o, nil
+}
 
-	bytes, err := ioutil.ReadAll(file)
+func (w *world) loadShaderSource(shaderSrc string) (code *string, err error) {
+	bytes, err := fs.ReadFile(w.fsys, path.Join(api.ShaderDir, shaderSrc))
 	if err != nil {
 		return nil, err
 	}
@@ -155,6 +162,10 @@ func (w *world) RelativePath() string {
 	return w.relativePath
 }
 
+func (w *world) FS() fs.FS {
+	return w.fsys
+}
+
 func (w *world) Root() api.INode {
 	return w.root
 }
@@ -177,37 +188,119 @@ func (w *world) Push(scene api.INode) {
 	w.sceneGraph.PushNode(scene)
 }
 
+// RouteEvents records the event and tracks it for the input map and
+// state before handing it to the event targets. Thus actions see every
+// event even if a target marks it handled.
 func (w *world) RouteEvents(event api.IEvent) {
+	w.recorder.Capture(event)
+	w.input.Handle(event)
+	w.inputState.Handle(event)
 	w.NodeManager().RouteEvents(event)
 }
 
+func (w *world) EventQueue() api.IEventQueue {
+	return w.events
+}
+
+func (w *world) Input() api.IInputMap {
+	return w.input
+}
+
+func (w *world) InputState() api.IInputState {
+	return w.inputState
+}
+
+func (w *world) Gamepads() api.IGamepads {
+	return w.gamepads
+}
+
+func (w *world) Recorder() api.IInputRecorder {
+	return w.recorder
+}
+
 func (w *world) Configure() error {
 
 	w.viewSpace = maths.NewMatrix4()
 	w.invViewSpace = maths.NewMatrix4()
 
-	fmt.Println("Loading Raster font...")
-	w.rasterFont = fonts.NewRasterFont()
-	err := w.rasterFont.Initialize("raster_font.data", w.relativePath)
+	engProps := w.Properties().Engine
+	w.profiler = timing.NewProfiler(engProps.ProfileFrames)
+	w.profiler.SetEnabled(engProps.Profile)
 
+	w.hotReloader = reload.NewHotReloader(time.Duration(engProps.HotReloadPoll) * time.Millisecond)
+	w.hotReloader.SetEnabled(engProps.HotReload)
+
+	w.gamepads.SetDeadZone(engProps.GamepadDeadZone)
+
+	fmt.Println("Loading Raster font...")
+	font, err := w.assets.Load(api.AssetRasterFont, "RasterFont", "raster_font.data")
 	if err != nil {
-		return nil
+		return err
 	}
+	w.rasterFont = font.(api.IRasterFont)
 
 	// ------------------------------------------------------------
 	// Projection space
 	// ------------------------------------------------------------
-	camera := w.Properties().Camera
 	wp := w.Properties().Window
 
-	projection := display.NewCamera()
-	projection.SetProjection(
+	// The Orientation decides which side of the virtual resolution
+	// is the longer one.
+	vr := wp.VirtualRes
+	if (wp.Orientation == "Portrait" && vr.Width > vr.Height) ||
+		(wp.Orientation == "Landscape" && vr.Height > vr.Width) {
+		vr.Width, vr.Height = vr.Height, vr.Width
+	}
+
+	w.viewport = display.NewViewport()
+	w.viewport.Configure(wp.ScalePolicy, vr.Width, vr.Height)
+	w.viewport.Resize(wp.DeviceRes.Width, wp.DeviceRes.Height)
+
+	w.camera = display.NewCamera()
+	w.configureProjection()
+
+	w.projection = w.camera.Matrix()
+
+	if engProps.Backend == api.BackendSoftware {
+		w.rasterizer = raster.NewRasterizer(wp.DeviceRes.Width, wp.DeviceRes.Height, w)
+	}
+
+	return nil
+}
+
+// configureProjection sizes the projection to the viewport's view size.
+func (w *world) configureProjection() {
+	camera := w.Properties().Camera
+	width, height := w.viewport.ViewSize()
+
+	w.camera.SetProjection(
 		0.0, 0.0, // bottom,left
-		float32(wp.DeviceRes.Height), float32(wp.DeviceRes.Width), // top,right
+		height, width, // top,right
 		camera.Depth.Near, camera.Depth.Far)
+}
 
-	w.projection = projection.Matrix()
-	return nil
+// Resize is called by the display when the framebuffer changes size.
+func (w *world) Resize(width, height int) {
+	if w.viewport == nil || width <= 0 || height <= 0 {
+		return
+	}
+
+	dvr := &w.Properties().Window.DeviceRes
+	dvr.Width = width
+	dvr.Height = height
+
+	w.viewport.Resize(width, height)
+	w.configureProjection()
+
+	if w.rasterizer != nil {
+		w.rasterizer.Resize(width, height)
+	}
+
+	w.sceneGraph.Resize()
+}
+
+func (w *world) Viewport() api.IViewport {
+	return w.viewport
 }
 
 func (w *world) Projection() api.IMatrix4 {
@@ -223,11 +316,12 @@ func (w *world) InvertedViewspace() api.IMatrix4 {
 }
 
 func (w *world) AddAtlas(name string, atlas api.IAtlasX) {
-	w.atlases[name] = atlas
+	w.assets.Add(api.AssetAtlas, name, atlas)
 }
 
 func (w *world) GetAtlas(name string) api.IAtlasX {
-	return w.atlases[name]
+	atlas, _ := w.assets.Get(api.AssetAtlas, name).(api.IAtlasX)
+	return atlas
 }
 
 func (w *world) RasterFont() api.IRasterFont {
@@ -238,24 +332,10 @@ func (w *world) Properties() *configuration.Properties {
 	return w.properties
 }
 
-func (w *world) PropertiesOverride(configFile string) {
-	eConfFile, err := os.Open(configFile)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
-
-	defer eConfFile.Close()
-
-	bytes, err := ioutil.ReadAll(eConfFile)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
-
-	// Merge on top other existing property values
-	err = json.Unmarshal(bytes, w.properties)
-	if err != nil {
-		log.Fatalln("ERROR:", err)
-	}
+func (w *world) PropertiesOverride(configFile string) error {
+	// Merge on top other existing property values. The file is
+	// relative to the world's FS like every other engine file.
+	return w.properties.LoadFS(w.fsys, configFile)
 }
 
 func (w *world) Fps() int {
@@ -281,3 +361,23 @@ func (w *world) AvgRender() float64 {
 func (w *world) SetAvgRender(v float64) {
 	w.renderTime = v
 }
+
+func (w *world) DebugController() api.IDebugController {
+	return w.debugController
+}
+
+func (w *world) Profiler() api.IProfiler {
+	return w.profiler
+}
+
+func (w *world) HotReloader() api.IHotReloader {
+	return w.hotReloader
+}
+
+func (w *world) Assets() api.IAssetManager {
+	return w.assets
+}
+
+func (w *world) Rasterizer() api.IRasterizer {
+	return w.rasterizer
+}
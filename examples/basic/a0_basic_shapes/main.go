@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
 	"github.com/wdevore/Ranger-Go-IGE/engine"
@@ -16,8 +17,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	defer engine.End()
-
 	world := engine.World()
 
 	// -----------------------------------------------------
@@ -58,4 +57,6 @@ func main() {
 
 	// And finally we can start the game.
 	engine.Begin()
+	engine.End()
+	os.Exit(engine.ExitCode())
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/wdevore/Ranger-Go-IGE/api"
 	"github.com/wdevore/Ranger-Go-IGE/engine"
@@ -14,8 +15,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	defer engine.End()
-
 	world := engine.World()
 
 	// -----------------------------------------------------
@@ -69,7 +68,6 @@ func main() {
 
 	// And finally we can start the game.
 	engine.Begin()
-	if err != nil {
-		panic(err)
-	}
+	engine.End()
+	os.Exit(engine.ExitCode())
 }
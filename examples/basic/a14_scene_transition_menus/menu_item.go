@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
+)
+
+// menuItem is a focusable line of text. Enter, or the gamepad's A
+// button, selects the focused item.
+type menuItem struct {
+	nodes.Node
+
+	label string
+	text  *shapes.BitmapFont9x9Node
+
+	selected func()
+}
+
+func newMenuItem(name, label string, atlas api.IAtlasX, world api.IWorld, parent api.INode, selected func()) (*menuItem, error) {
+	o := new(menuItem)
+	o.Initialize(name)
+	o.SetParent(parent)
+	o.label = label
+	o.selected = selected
+	parent.AddChild(o)
+
+	if err := o.build(atlas, world); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (m *menuItem) build(atlas api.IAtlasX, world api.IWorld) error {
+	m.Node.Build(world)
+
+	textureNode, err := shapes.NewBitmapFont9x9Node("Label", atlas, world, m)
+	if err != nil {
+		return err
+	}
+	m.text = textureNode.(*shapes.BitmapFont9x9Node)
+	m.text.SetText(m.label)
+
+	return nil
+}
+
+func (m *menuItem) setStyle(scale float32, textColor api.IPalette) {
+	m.text.SetScale(scale)
+	m.text.SetColor(textColor.Array())
+}
+
+// -----------------------------------------------------
+// IFocusable
+// -----------------------------------------------------
+
+func (m *menuItem) Focusable() bool {
+	return true
+}
+
+func (m *menuItem) Focus() {
+	m.text.SetText("> " + m.label)
+}
+
+func (m *menuItem) Blur() {
+	m.text.SetText(m.label)
+}
+
+func (m *menuItem) Handle(event api.IEvent) bool {
+	switch event.GetType() {
+	case api.IOTypeKeyboard:
+		if event.GetState() != api.KeyPress || event.GetKeyCode() != api.KeyEnter {
+			return false
+		}
+	case api.IOTypeGamepadButtonDown:
+		if event.GetButton() != api.GamepadButtonA {
+			return false
+		}
+	default:
+		return false
+	}
+
+	m.selected()
+
+	return true
+}
@@ -11,14 +11,16 @@ type menuLayer struct {
 	nodes.Node
 
 	atlas api.IAtlasX
+	menu  *sceneMenu
 }
 
-func newMenuLayer(name string, atlas api.IAtlasX, world api.IWorld, parent api.INode) (api.INode, error) {
+func newMenuLayer(name string, atlas api.IAtlasX, world api.IWorld, menu *sceneMenu) (api.INode, error) {
 	o := new(menuLayer)
 	o.Initialize(name)
-	o.SetParent(parent)
+	o.SetParent(menu)
 	o.atlas = atlas
-	parent.AddChild(o)
+	o.menu = menu
+	menu.AddChild(o)
 
 	if err := o.build(world); err != nil {
 		return nil, err
@@ -39,12 +41,44 @@ func (g *menuLayer) build(world api.IWorld) error {
 	gsq := square.(*shapes.MonoSquareNode)
 	gsq.SetFilledColor(color.NewPaletteInt64(color.GoldYellow))
 
-	g.addLine("Select Choice", -100.0, 100.0, 25, color.NewPaletteInt64(color.White), world)
+	err = g.addLine("Select Choice", -100.0, 100.0, 25, color.NewPaletteInt64(color.White), world)
+	if err != nil {
+		return err
+	}
 
-	g.addLine("1 = Settings", -100.0, 65.0, 25, color.NewPaletteInt64(color.Lime), world)
-	g.addLine("2 = Highscore", -100.0, 40.0, 25, color.NewPaletteInt64(color.Pink), world)
-	g.addLine("3 = Game", -100.0, 15.0, 25, color.NewPaletteInt64(color.Yellow), world)
-	g.addLine("x = To Exit", -100.0, -10.0, 25, color.NewPaletteInt64(color.Red), world)
+	// Items are navigated with the arrow keys, Tab or the d-pad.
+	err = g.addItem("Settings", -100.0, 65.0, 25, color.NewPaletteInt64(color.Lime), world,
+		func() { g.menu.showSubMenu("Settings") })
+	if err != nil {
+		return err
+	}
+	err = g.addItem("Highscore", -100.0, 40.0, 25, color.NewPaletteInt64(color.Pink), world,
+		func() { g.menu.showSubMenu("Highscore") })
+	if err != nil {
+		return err
+	}
+	err = g.addItem("Game", -100.0, 15.0, 25, color.NewPaletteInt64(color.Yellow), world,
+		func() { g.menu.showSubMenu("Game") })
+	if err != nil {
+		return err
+	}
+
+	err = g.addItem("Exit", -100.0, -10.0, 25, color.NewPaletteInt64(color.Red), world,
+		g.menu.exit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (g *menuLayer) addItem(label string, x, y, s float32, textColor api.IPalette, world api.IWorld, selected func()) error {
+	item, err := newMenuItem(label+"Item", label, g.atlas, world, g, selected)
+	if err != nil {
+		return err
+	}
+	item.SetPosition(x, y)
+	item.setStyle(s, textColor)
 
 	return nil
 }
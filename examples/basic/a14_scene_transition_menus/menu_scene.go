@@ -56,7 +56,10 @@ func (s *sceneMenu) build(world api.IWorld) error {
 	bn := bg.(*backgroundNode)
 	bn.setColor(color.NewPaletteInt64(color.LightGray))
 
-	newMenuLayer("Menu Layer", s.atlas, world, s)
+	_, err = newMenuLayer("Menu Layer", s.atlas, world, s)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -75,6 +78,8 @@ func (s *sceneMenu) Update(msPerUpdate, secPerUpdate float64) {
 
 		if isFinished {
 			s.setState("Update: ", api.SceneOnStage)
+			// Focus the first item
+			s.World().NodeManager().Focus().Navigate(api.FocusNext)
 		}
 
 		if s.enterExitState == 0 {
@@ -154,77 +159,40 @@ func (s *sceneMenu) EnterScene(man api.INodeManager) {
 		s.SetPosition(0.0, float32(vrs.Height))
 	}
 	man.RegisterTarget(s)
-	man.RegisterEventTarget(s)
 }
 
 // ExitScene called when a node is exiting stage
 func (s *sceneMenu) ExitScene(man api.INodeManager) bool {
 	// fmt.Println("sceneMenu ExitScene")
 	man.UnRegisterTarget(s)
-	man.UnRegisterEventTarget(s)
 	s.setState("ExitScene: ", api.SceneOffStage)
 	return false
 }
 
-func (s *sceneMenu) Handle(event api.IEvent) bool {
+// showSubMenu transitions to the named sub menu. This menu is pushed
+// first so it returns when the sub menu exits.
+func (s *sceneMenu) showSubMenu(name string) {
 	if s.CurrentState() != api.SceneOnStage {
-		return false
+		return
 	}
 
-	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println("sceneMenu", event.GetKeyScan())
-		// fmt.Println(event)
-
-		if event.GetState() == 1 {
-			switch event.GetKeyScan() {
-			case 49: // 1 Settings
-				s.enterExitState = 1
-				// Push this Menu scene first
-				s.World().Push(s)
-				// Push Settings scene next
-				for _, subMenu := range s.subMenus {
-					if subMenu.Name() == "Settings" {
-						s.World().Push(subMenu)
-						break
-					}
-				}
-				// Signal NM that this scene wants to transition out.
-				s.setState("Notify T: ", api.SceneTransitionStartOut)
-				return true
-			case 50: // 2 HighScore
-				s.enterExitState = 1
-				// Push this Menu scene first
-				s.World().Push(s)
-				// Push Settings scene next
-				for _, subMenu := range s.subMenus {
-					if subMenu.Name() == "Highscore" {
-						s.World().Push(subMenu)
-						break
-					}
-				}
-				// Signal NM that this scene wants to transition out.
-				s.setState("Notify T: ", api.SceneTransitionStartOut)
-				return true
-			case 51: // 3 Game
-				s.enterExitState = 1
-				// Push this Menu scene first
-				s.World().Push(s)
-				// Push Settings scene next
-				for _, subMenu := range s.subMenus {
-					if subMenu.Name() == "Game" {
-						s.World().Push(subMenu)
-						break
-					}
-				}
-				// Signal NM that this scene wants to transition out.
-				s.setState("Notify T: ", api.SceneTransitionStartOut)
-				return true
-			case 88: // x
-				s.enterExitState = 0
-				s.setState("Handle: ", api.SceneTransitionStartOut)
-			}
+	s.enterExitState = 1
+	s.World().Push(s)
+	for _, subMenu := range s.subMenus {
+		if subMenu.Name() == name {
+			s.World().Push(subMenu)
+			break
 		}
 	}
+	// Signal NM that this scene wants to transition out.
+	s.setState("Notify T: ", api.SceneTransitionStartOut)
+}
+
+func (s *sceneMenu) exit() {
+	if s.CurrentState() != api.SceneOnStage {
+		return
+	}
 
-	return false
+	s.enterExitState = 0
+	s.setState("Handle: ", api.SceneTransitionStartOut)
 }
@@ -139,11 +139,11 @@ func (s *settingsMenu) Handle(event api.IEvent) bool {
 	}
 
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println("settingsMenu ", event.GetKeyScan())
+		// fmt.Println("settingsMenu ", event.GetKeyCode())
 		// fmt.Println(event)
 
 		if event.GetState() == 1 {
-			switch event.GetKeyScan() {
+			switch event.GetKeyCode() {
 			case 82: // r
 				//
 				// Signal NM that this scene wants to transition out.
@@ -136,8 +136,8 @@ func (c *customRectangleNode) Handle(event api.IEvent) bool {
 	if event.GetType() == api.IOTypeMouseMotion {
 		c.mx, c.my = event.GetMousePosition()
 	} else if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
-		switch event.GetKeyScan() {
+		// fmt.Println(event.GetKeyCode())
+		switch event.GetKeyCode() {
 		case 82: // r
 			if event.GetState() == 1 {
 				c.rotationEnabled = !c.rotationEnabled
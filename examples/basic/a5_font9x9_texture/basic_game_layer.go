@@ -92,13 +92,13 @@ func (g *gameLayer) ExitNode(man api.INodeManager) {
 
 func (g *gameLayer) Handle(event api.IEvent) bool {
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
+		// fmt.Println(event.GetKeyCode())
 		// fmt.Println(event)
 
 		if event.GetState() == 1 || event.GetState() == 2 {
 			bf := g.textureNode.(*shapes.BitmapFont9x9Node)
 			bf.SetColor(color.NewPaletteInt64(color.GoldYellow).Array())
-			switch event.GetKeyScan() {
+			switch event.GetKeyCode() {
 			case 68: // d
 				tn := g.textureNode.(*shapes.BitmapFont9x9Node)
 				tn.SetIndex(1)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/wdevore/Ranger-Go-IGE/engine"
 	"github.com/wdevore/Ranger-Go-IGE/extras"
@@ -13,8 +14,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	defer engine.End()
-
 	world := engine.World()
 
 	// ------------------------------------------------------------
@@ -43,4 +42,6 @@ func main() {
 
 	// And finally we can start the game.
 	engine.Begin()
+	engine.End()
+	os.Exit(engine.ExitCode())
 }
@@ -238,11 +238,11 @@ func (g *gameLayer) ExitNode(man api.INodeManager) {
 
 func (g *gameLayer) Handle(event api.IEvent) bool {
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
+		// fmt.Println(event.GetKeyCode())
 		// fmt.Println(event)
 
 		if event.GetState() == 1 || event.GetState() == 2 {
-			switch event.GetKeyScan() {
+			switch event.GetKeyCode() {
 			case 68: // d
 				g.orangeSqrPhyComp.ApplyForce(0.0, 500.0)
 			case 70: // f
@@ -131,8 +131,8 @@ func (g *gameLayer) ExitNode(man api.INodeManager) {
 
 func (g *gameLayer) Handle(event api.IEvent) bool {
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
-		switch event.GetKeyScan() {
+		// fmt.Println(event.GetKeyCode())
+		switch event.GetKeyCode() {
 		case 65: // A
 			if event.GetState() == 1 {
 			}
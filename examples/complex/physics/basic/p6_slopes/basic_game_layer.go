@@ -141,10 +141,10 @@ func (g *gameLayer) ExitNode(man api.INodeManager) {
 
 func (g *gameLayer) Handle(event api.IEvent) bool {
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
+		// fmt.Println(event.GetKeyCode())
 		// fmt.Println(event)
 		if event.GetState() == 0 {
-			switch event.GetKeyScan() {
+			switch event.GetKeyCode() {
 			case 65: // A = left
 				g.leftKeyDown = false
 			case 87: // W = up
@@ -157,7 +157,7 @@ func (g *gameLayer) Handle(event api.IEvent) bool {
 		}
 
 		if event.GetState() == 1 || event.GetState() == 2 {
-			switch event.GetKeyScan() {
+			switch event.GetKeyCode() {
 			case 65: // A = left
 				g.leftKeyDown = true
 			case 87: // W = up
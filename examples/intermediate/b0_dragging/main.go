@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/wdevore/Ranger-Go-IGE/engine"
 	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
@@ -14,8 +15,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	defer engine.End()
-
 	world := engine.World()
 
 	splash, err := newBasicSplashScene("Splash", world)
@@ -32,5 +31,7 @@ func main() {
 	world.Push(boot)
 
 	engine.Begin()
+	engine.End()
+	os.Exit(engine.ExitCode())
 
 }
@@ -132,8 +132,8 @@ func (g *gameLayer) Handle(event api.IEvent) bool {
 	g.particleSystem.SetPosition(g.dragSquare.Position().X(), g.dragSquare.Position().Y())
 
 	if event.GetType() == api.IOTypeKeyboard {
-		// fmt.Println(event.GetKeyScan())
-		switch event.GetKeyScan() {
+		// fmt.Println(event.GetKeyCode())
+		switch event.GetKeyCode() {
 		case 65: // A
 			if event.GetState() == 1 {
 				g.autoTriggerEnable = !g.autoTriggerEnable
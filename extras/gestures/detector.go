@@ -0,0 +1,314 @@
+package gestures
+
+import (
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/extras/misc"
+)
+
+// Recognizer states
+const (
+	statePossible = iota
+	stateActive
+	stateFailed
+)
+
+type recognizer interface {
+	kind() int
+	state() int
+	setState(state int)
+
+	press(d *detector)
+	move(d *detector)
+	release(d *detector)
+	elapsed(d *detector)
+	// cancel emits GestureCancelled if a gesture is in progress.
+	cancel(d *detector)
+}
+
+type sample struct {
+	time int64
+	x, y float32
+}
+
+// track is the press being followed.
+type track struct {
+	mods   int
+	clicks int
+
+	// Device-space
+	downX, downY float32
+	// moved is set once the pointer leaves the Slop.
+	moved bool
+
+	heldMs float64
+
+	// Parent-space
+	down     api.IPoint
+	position api.IPoint
+	velocity api.IPoint
+
+	samples []sample
+}
+
+type detector struct {
+	node api.INode
+
+	recognizers  []recognizer
+	simultaneous map[[2]int]bool
+	pinchMod     int
+
+	listener func(api.IGesture)
+
+	tracking bool
+	track    track
+
+	// Parent-space movement between events
+	dragging api.IDragging
+}
+
+// NewGestureDetector creates a detector for node without any
+// recognizers. The node's parent must have been built with a world.
+func NewGestureDetector(node api.INode) api.IGestureDetector {
+	o := new(detector)
+	o.node = node
+	o.simultaneous = make(map[[2]int]bool)
+	o.pinchMod = api.ModControl
+	o.track.down = geometry.NewPoint()
+	o.track.position = geometry.NewPoint()
+	o.track.velocity = geometry.NewPoint()
+	o.dragging = misc.NewDragState()
+
+	o.Simultaneous(api.GestureDrag, api.GestureFling)
+	o.Simultaneous(api.GesturePinch, api.GestureRotate)
+
+	return o
+}
+
+func (d *detector) Recognize(kind int) {
+	var r recognizer
+
+	switch kind {
+	case api.GestureTap:
+		r = newTapRecognizer(d.node)
+	case api.GestureLongPress:
+		r = newLongPressRecognizer(d.node)
+	case api.GestureDrag:
+		r = newDragRecognizer(d.node)
+	case api.GestureFling:
+		r = newFlingRecognizer(d.node)
+	case api.GesturePinch, api.GestureRotate:
+		r = newPinchRecognizer(kind, d.node)
+	default:
+		return
+	}
+
+	d.recognizers = append(d.recognizers, r)
+}
+
+func (d *detector) Simultaneous(kindA, kindB int) {
+	d.simultaneous[[2]int{kindA, kindB}] = true
+	d.simultaneous[[2]int{kindB, kindA}] = true
+}
+
+func (d *detector) SetPinchModifier(mod int) {
+	d.pinchMod = mod
+}
+
+func (d *detector) SetListener(listener func(api.IGesture)) {
+	d.listener = listener
+}
+
+func (d *detector) Handle(event api.IEvent) bool {
+	switch event.GetType() {
+	case api.IOTypeMouseButtonDown:
+		if event.GetButton() != api.MouseButtonLeft {
+			return false
+		}
+		// A press while tracking means the release was lost, start over.
+		if d.tracking {
+			d.Cancel()
+		}
+		if !d.pressedOnNode(event) {
+			return false
+		}
+
+		d.begin(event)
+		for _, r := range d.recognizers {
+			if r.state() != stateFailed {
+				r.press(d)
+			}
+		}
+		return true
+	case api.IOTypeMouseMotion:
+		if !d.tracking {
+			return false
+		}
+
+		d.follow(event)
+		for _, r := range d.recognizers {
+			if r.state() != stateFailed {
+				r.move(d)
+			}
+		}
+		return true
+	case api.IOTypeMouseButtonUp:
+		if !d.tracking || event.GetButton() != api.MouseButtonLeft {
+			return false
+		}
+
+		d.follow(event)
+		x, y := event.GetMousePosition()
+		d.dragging.SetButtonStateUsing(x, y, event.GetButton(), api.KeyRelease, d.node)
+		for _, r := range d.recognizers {
+			if r.state() != stateFailed {
+				r.release(d)
+			}
+		}
+		d.tracking = false
+		return true
+	}
+
+	return false
+}
+
+// pressedOnNode checks the node, or one of its children, is under the
+// press. Events routed to registered targets have no target so one is
+// found.
+func (d *detector) pressedOnNode(event api.IEvent) bool {
+	target := event.GetTarget()
+	if target == nil && d.node.World() != nil {
+		target = d.node.World().NodeManager().HitTest(event.GetMousePosition())
+	}
+
+	for p := target; p != nil; p = p.Parent() {
+		if p == d.node {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *detector) begin(event api.IEvent) {
+	d.tracking = true
+
+	for _, r := range d.recognizers {
+		r.setState(statePossible)
+	}
+
+	x, y := event.GetMousePosition()
+
+	t := &d.track
+	t.mods = int(event.GetKeyMotif())
+	t.clicks = int(event.GetClicks())
+	t.downX = float32(x)
+	t.downY = float32(y)
+	t.moved = false
+	t.heldMs = 0.0
+	t.velocity.SetByComp(0.0, 0.0)
+	t.samples = t.samples[:0]
+
+	d.dragging.SetButtonStateUsing(x, y, event.GetButton(), api.KeyPress, d.node)
+
+	nodes.MapDeviceToNode(x, y, d.node.Parent(), t.down)
+	t.position.SetByPoint(t.down)
+	d.sample(event.GetTimestamp())
+}
+
+func (d *detector) follow(event api.IEvent) {
+	x, y := event.GetMousePosition()
+
+	t := &d.track
+	if !t.moved {
+		t.moved = float32(math.Hypot(float64(float32(x)-t.downX), float64(float32(y)-t.downY))) > Slop
+	}
+
+	d.dragging.SetMotionStateUsing(x, y, event.GetState(), d.node)
+
+	nodes.MapDeviceToNode(x, y, d.node.Parent(), t.position)
+	d.sample(event.GetTimestamp())
+}
+
+// sample records the position and measures the velocity across the
+// velocityWindow.
+func (d *detector) sample(now int64) {
+	t := &d.track
+	t.samples = append(t.samples, sample{time: now, x: t.position.X(), y: t.position.Y()})
+
+	// Keep one sample at or beyond the window.
+	first := 0
+	for first < len(t.samples)-1 && now-t.samples[first+1].time >= velocityWindow {
+		first++
+	}
+	t.samples = t.samples[first:]
+
+	oldest := t.samples[0]
+	dt := float32(now-oldest.time) / 1000000000.0
+	if dt > 0.0 {
+		t.velocity.SetByComp((t.position.X()-oldest.x)/dt, (t.position.Y()-oldest.y)/dt)
+	}
+}
+
+func (d *detector) Update(msPerUpdate float64) {
+	if !d.tracking {
+		return
+	}
+
+	d.track.heldMs += msPerUpdate
+	for _, r := range d.recognizers {
+		if r.state() != stateFailed {
+			r.elapsed(d)
+		}
+	}
+}
+
+func (d *detector) Cancel() {
+	for _, r := range d.recognizers {
+		if r.state() == stateActive {
+			r.cancel(d)
+		}
+		r.setState(stateFailed)
+	}
+	d.tracking = false
+}
+
+// claim makes r active unless a conflicting recognizer already is.
+// Possible recognizers that conflict with r then fail.
+func (d *detector) claim(r recognizer) bool {
+	for _, o := range d.recognizers {
+		if o != r && o.state() == stateActive && !d.together(o, r) {
+			r.setState(stateFailed)
+			return false
+		}
+	}
+
+	for _, o := range d.recognizers {
+		if o != r && o.state() == statePossible && !d.together(o, r) {
+			o.setState(stateFailed)
+		}
+	}
+
+	r.setState(stateActive)
+
+	return true
+}
+
+func (d *detector) together(a, b recognizer) bool {
+	return d.simultaneous[[2]int{a.kind(), b.kind()}]
+}
+
+// emit fills in the pointer's position and velocity and reports the
+// gesture.
+func (d *detector) emit(g *gesture, phase int) {
+	g.phase = phase
+	g.position.SetByPoint(d.track.position)
+	g.velocity.SetByPoint(d.track.velocity)
+
+	if d.listener != nil {
+		d.listener(g)
+	}
+}
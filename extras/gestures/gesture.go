@@ -0,0 +1,89 @@
+// Package gestures recognizes taps, long presses, drags, flings,
+// pinches and rotations from the pointer events a node handles.
+package gestures
+
+import (
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+)
+
+const (
+	// Slop is how far, in device pixels, a press may wander and still
+	// be a tap or long press. Moving further starts a drag.
+	Slop = float32(8.0)
+	// LongPressDuration is how long, in milliseconds, a press is held
+	// before it is a long press. A longer press is no longer a tap.
+	LongPressDuration = 500.0
+	// FlingSpeed is the slowest release, in parent-space units per
+	// second, that is a fling.
+	FlingSpeed = float32(500.0)
+	// velocityWindow is how far back, in nanoseconds, velocity is
+	// measured.
+	velocityWindow = int64(100000000)
+)
+
+type gesture struct {
+	kind  int
+	phase int
+	node  api.INode
+
+	position api.IPoint
+	delta    api.IPoint
+	velocity api.IPoint
+
+	direction int
+	scale     float32
+	rotation  float64
+	taps      int
+}
+
+func newGesture(kind int, node api.INode) *gesture {
+	o := new(gesture)
+	o.kind = kind
+	o.node = node
+	o.position = geometry.NewPoint()
+	o.delta = geometry.NewPoint()
+	o.velocity = geometry.NewPoint()
+	o.scale = 1.0
+	return o
+}
+
+func (g *gesture) Kind() int {
+	return g.kind
+}
+
+func (g *gesture) Phase() int {
+	return g.phase
+}
+
+func (g *gesture) Node() api.INode {
+	return g.node
+}
+
+func (g *gesture) Position() api.IPoint {
+	return g.position
+}
+
+func (g *gesture) Delta() api.IPoint {
+	return g.delta
+}
+
+func (g *gesture) Velocity() api.IPoint {
+	return g.velocity
+}
+
+func (g *gesture) Direction() int {
+	return g.direction
+}
+
+func (g *gesture) Scale() float32 {
+	return g.scale
+}
+
+func (g *gesture) Rotation() float64 {
+	return g.rotation
+}
+
+func (g *gesture) Taps() int {
+	return g.taps
+}
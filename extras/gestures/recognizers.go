@@ -0,0 +1,275 @@
+package gestures
+
+import (
+	"math"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+)
+
+// base is embedded by the recognizers. The hooks a recognizer doesn't
+// need do nothing.
+type base struct {
+	current int
+	gesture *gesture
+}
+
+func (b *base) kind() int {
+	return b.gesture.kind
+}
+
+func (b *base) state() int {
+	return b.current
+}
+
+func (b *base) setState(state int) {
+	b.current = state
+}
+
+func (b *base) press(d *detector)   {}
+func (b *base) move(d *detector)    {}
+func (b *base) release(d *detector) {}
+func (b *base) elapsed(d *detector) {}
+
+func (b *base) cancel(d *detector) {
+	d.emit(b.gesture, api.GestureCancelled)
+}
+
+// --------------------------------------------------------------------------
+// Tap: released within the Slop before LongPressDuration.
+// --------------------------------------------------------------------------
+
+type tapRecognizer struct {
+	base
+}
+
+func newTapRecognizer(node api.INode) recognizer {
+	o := new(tapRecognizer)
+	o.gesture = newGesture(api.GestureTap, node)
+	return o
+}
+
+func (r *tapRecognizer) move(d *detector) {
+	if d.track.moved {
+		r.setState(stateFailed)
+	}
+}
+
+func (r *tapRecognizer) elapsed(d *detector) {
+	if d.track.heldMs >= LongPressDuration {
+		r.setState(stateFailed)
+	}
+}
+
+func (r *tapRecognizer) release(d *detector) {
+	if d.claim(r) {
+		r.gesture.taps = d.track.clicks
+		if r.gesture.taps < 1 {
+			r.gesture.taps = 1
+		}
+		d.emit(r.gesture, api.GestureEnded)
+	}
+}
+
+// The tap is reported in one go so there is nothing to cancel.
+func (r *tapRecognizer) cancel(d *detector) {}
+
+// --------------------------------------------------------------------------
+// Long press: held within the Slop for LongPressDuration. Movement
+// afterwards is reported until release.
+// --------------------------------------------------------------------------
+
+type longPressRecognizer struct {
+	base
+}
+
+func newLongPressRecognizer(node api.INode) recognizer {
+	o := new(longPressRecognizer)
+	o.gesture = newGesture(api.GestureLongPress, node)
+	return o
+}
+
+func (r *longPressRecognizer) move(d *detector) {
+	switch r.state() {
+	case statePossible:
+		if d.track.moved {
+			r.setState(stateFailed)
+		}
+	case stateActive:
+		d.emit(r.gesture, api.GestureChanged)
+	}
+}
+
+func (r *longPressRecognizer) elapsed(d *detector) {
+	if r.state() == statePossible && d.track.heldMs >= LongPressDuration && d.claim(r) {
+		d.emit(r.gesture, api.GestureBegan)
+	}
+}
+
+func (r *longPressRecognizer) release(d *detector) {
+	if r.state() == stateActive {
+		d.emit(r.gesture, api.GestureEnded)
+	} else {
+		r.setState(stateFailed)
+	}
+}
+
+// --------------------------------------------------------------------------
+// Drag: moved beyond the Slop. Began's Delta covers the movement from
+// the press.
+// --------------------------------------------------------------------------
+
+type dragRecognizer struct {
+	base
+}
+
+func newDragRecognizer(node api.INode) recognizer {
+	o := new(dragRecognizer)
+	o.gesture = newGesture(api.GestureDrag, node)
+	return o
+}
+
+func (r *dragRecognizer) move(d *detector) {
+	switch r.state() {
+	case statePossible:
+		if d.track.moved && d.claim(r) {
+			t := &d.track
+			r.gesture.delta.SetByComp(t.position.X()-t.down.X(), t.position.Y()-t.down.Y())
+			d.emit(r.gesture, api.GestureBegan)
+		}
+	case stateActive:
+		r.gesture.delta.SetByPoint(d.dragging.Delta())
+		d.emit(r.gesture, api.GestureChanged)
+	}
+}
+
+func (r *dragRecognizer) release(d *detector) {
+	if r.state() == stateActive {
+		r.gesture.delta.SetByComp(0.0, 0.0)
+		d.emit(r.gesture, api.GestureEnded)
+	}
+}
+
+// --------------------------------------------------------------------------
+// Fling: released beyond the Slop at FlingSpeed or faster.
+// --------------------------------------------------------------------------
+
+type flingRecognizer struct {
+	base
+}
+
+func newFlingRecognizer(node api.INode) recognizer {
+	o := new(flingRecognizer)
+	o.gesture = newGesture(api.GestureFling, node)
+	return o
+}
+
+func (r *flingRecognizer) release(d *detector) {
+	t := &d.track
+	vx, vy := t.velocity.X(), t.velocity.Y()
+
+	speed := float32(math.Hypot(float64(vx), float64(vy)))
+	if !t.moved || speed < FlingSpeed || !d.claim(r) {
+		r.setState(stateFailed)
+		return
+	}
+
+	switch {
+	case math.Abs(float64(vx)) >= math.Abs(float64(vy)) && vx < 0.0:
+		r.gesture.direction = api.SwipeLeft
+	case math.Abs(float64(vx)) >= math.Abs(float64(vy)):
+		r.gesture.direction = api.SwipeRight
+	case vy > 0.0:
+		r.gesture.direction = api.SwipeUp
+	default:
+		r.gesture.direction = api.SwipeDown
+	}
+
+	d.emit(r.gesture, api.GestureEnded)
+}
+
+func (r *flingRecognizer) cancel(d *detector) {}
+
+// --------------------------------------------------------------------------
+// Pinch and Rotate: a press with the pinch modifier held claims the
+// press straight away. The emulated second pointer mirrors the cursor
+// through the node's origin so only the cursor's distance and angle
+// from the origin matter.
+// --------------------------------------------------------------------------
+
+type pinchRecognizer struct {
+	base
+
+	began bool
+
+	// The cursor relative to the node's origin at the press
+	startDistance float64
+	startAngle    float64
+}
+
+func newPinchRecognizer(kind int, node api.INode) recognizer {
+	o := new(pinchRecognizer)
+	o.gesture = newGesture(kind, node)
+	return o
+}
+
+// relative returns the distance and angle of the cursor from the
+// node's origin, both in parent-space.
+func (r *pinchRecognizer) relative(d *detector) (float64, float64) {
+	origin := r.gesture.node.Position()
+	dx := float64(d.track.position.X() - origin.X())
+	dy := float64(d.track.position.Y() - origin.Y())
+	return math.Hypot(dx, dy), math.Atan2(dy, dx)
+}
+
+func (r *pinchRecognizer) press(d *detector) {
+	r.began = false
+
+	if d.track.mods&d.pinchMod == 0 {
+		r.setState(stateFailed)
+		return
+	}
+
+	r.startDistance, r.startAngle = r.relative(d)
+	if r.startDistance == 0.0 {
+		r.setState(stateFailed)
+		return
+	}
+
+	d.claim(r)
+}
+
+func (r *pinchRecognizer) move(d *detector) {
+	if r.state() != stateActive || !d.track.moved {
+		return
+	}
+
+	distance, angle := r.relative(d)
+	r.gesture.scale = float32(distance / r.startDistance)
+
+	rotation := angle - r.startAngle
+	if rotation > math.Pi {
+		rotation -= 2.0 * math.Pi
+	} else if rotation <= -math.Pi {
+		rotation += 2.0 * math.Pi
+	}
+	r.gesture.rotation = rotation
+
+	if r.began {
+		d.emit(r.gesture, api.GestureChanged)
+	} else {
+		r.began = true
+		d.emit(r.gesture, api.GestureBegan)
+	}
+}
+
+func (r *pinchRecognizer) release(d *detector) {
+	if r.state() == stateActive && r.began {
+		d.emit(r.gesture, api.GestureEnded)
+	}
+}
+
+func (r *pinchRecognizer) cancel(d *detector) {
+	if r.began {
+		d.emit(r.gesture, api.GestureCancelled)
+	}
+}
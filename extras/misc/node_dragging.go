@@ -70,7 +70,7 @@ func (d *dragState) SetMotionStateUsing(x, y int32, state uint32, node api.INode
 }
 
 func (d *dragState) SetButtonStateUsing(x, y int32, button uint8, state uint32, node api.INode) {
-	d.active = button == 1
+	d.active = button == 1 && state == 1
 	d.dragging = state == 1
 
 	nodes.MapDeviceToNode(x, y, node.Parent(), d.mapPoint)
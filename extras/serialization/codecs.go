@@ -0,0 +1,271 @@
+package serialization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/color"
+	"github.com/wdevore/Ranger-Go-IGE/extras"
+	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
+)
+
+// codec adapts a pair of functions to an INodeCodec.
+type codec struct {
+	encode func(node api.INode) (interface{}, error)
+	decode func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error)
+}
+
+// NewCodec creates a codec from an encode and a decode function. A nil
+// encode writes no properties.
+func NewCodec(
+	encode func(node api.INode) (interface{}, error),
+	decode func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error)) api.INodeCodec {
+	return &codec{encode: encode, decode: decode}
+}
+
+func (c *codec) Encode(node api.INode) (interface{}, error) {
+	if c.encode == nil {
+		return nil, nil
+	}
+	return c.encode(node)
+}
+
+func (c *codec) Decode(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error) {
+	return c.decode(name, properties, world, parent)
+}
+
+// shapeJSON holds the properties of every mono shape. Each shape only
+// writes the ones it has.
+type shapeJSON struct {
+	DrawStyle  string  `json:",omitempty"`
+	Centered   bool    `json:",omitempty"`
+	Segments   int     `json:",omitempty"`
+	StartAngle float64 `json:",omitempty"`
+	EndAngle   float64 `json:",omitempty"`
+
+	// RGBA, 0->1
+	FilledColor  []float32 `json:",omitempty"`
+	OutlineColor []float32 `json:",omitempty"`
+	Color        []float32 `json:",omitempty"`
+}
+
+// defaultSegments is used when a circle or arc doesn't give any.
+const defaultSegments = 12
+
+var drawStyles = map[int]string{
+	api.FILLED:       "Filled",
+	api.OUTLINED:     "Outlined",
+	api.FILLOUTLINED: "FillOutlined",
+}
+
+func (sj *shapeJSON) drawStyle() (int, error) {
+	if sj.DrawStyle == "" {
+		return api.FILLED, nil
+	}
+	for style, name := range drawStyles {
+		if name == sj.DrawStyle {
+			return style, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown DrawStyle '%s'", sj.DrawStyle)
+}
+
+func (sj *shapeJSON) segments() int {
+	if sj.Segments <= 0 {
+		return defaultSegments
+	}
+	return sj.Segments
+}
+
+func readShape(properties json.RawMessage) (*shapeJSON, error) {
+	sj := &shapeJSON{}
+	if len(properties) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(properties))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(sj)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sj, nil
+}
+
+func palette(c []float32) (api.IPalette, error) {
+	if len(c) != 4 {
+		return nil, fmt.Errorf("expected an RGBA color, got %v", c)
+	}
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3]), nil
+}
+
+// filledShape is a shape with a fill and an outline color.
+type filledShape interface {
+	FilledColor() api.IPalette
+	OutlineColor() api.IPalette
+	SetFilledColor(color api.IPalette)
+	SetOutlineColor(color api.IPalette)
+}
+
+// lineShape is a shape with a single color.
+type lineShape interface {
+	Color() api.IPalette
+	SetColor(color api.IPalette)
+}
+
+func (sj *shapeJSON) writeColors(node api.INode) {
+	switch s := node.(type) {
+	case filledShape:
+		sj.FilledColor = s.FilledColor().Array()
+		sj.OutlineColor = s.OutlineColor().Array()
+	case lineShape:
+		sj.Color = s.Color().Array()
+	}
+}
+
+// readColors sets the colors given, leaving the others at their
+// defaults.
+func (sj *shapeJSON) readColors(node api.INode) error {
+	switch s := node.(type) {
+	case filledShape:
+		if sj.FilledColor != nil {
+			c, err := palette(sj.FilledColor)
+			if err != nil {
+				return err
+			}
+			s.SetFilledColor(c)
+		}
+		if sj.OutlineColor != nil {
+			c, err := palette(sj.OutlineColor)
+			if err != nil {
+				return err
+			}
+			s.SetOutlineColor(c)
+		}
+	case lineShape:
+		if sj.Color != nil {
+			c, err := palette(sj.Color)
+			if err != nil {
+				return err
+			}
+			s.SetColor(c)
+		}
+	}
+	return nil
+}
+
+// shapeCodec builds codecs for the mono shapes. describe fills in the
+// shape parameters and build constructs the shape from them.
+func shapeCodec(
+	describe func(node api.INode, sj *shapeJSON),
+	build func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error)) api.INodeCodec {
+	return NewCodec(
+		func(node api.INode) (interface{}, error) {
+			sj := &shapeJSON{}
+			describe(node, sj)
+			sj.writeColors(node)
+			return sj, nil
+		},
+		func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error) {
+			sj, err := readShape(properties)
+			if err != nil {
+				return nil, err
+			}
+			style, err := sj.drawStyle()
+			if err != nil {
+				return nil, err
+			}
+
+			node, err := build(name, sj, style, world, parent)
+			if err != nil {
+				return nil, err
+			}
+
+			return node, sj.readColors(node)
+		})
+}
+
+func registerBuiltIns(s api.ISceneSerializer) {
+	group, _ := extras.NewGroupNode("", nil, nil)
+	s.Register("Group", group, NewCodec(nil,
+		func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error) {
+			return extras.NewGroupNode(name, world, parent)
+		}))
+
+	s.Register("Nil", &extras.NilNode{}, NewCodec(nil,
+		func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error) {
+			node, err := extras.NewNilNode(name)
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil {
+				node.SetParent(parent)
+				parent.AddChild(node)
+			}
+			return node, nil
+		}))
+
+	s.Register("MonoSquare", &shapes.MonoSquareNode{}, shapeCodec(
+		func(node api.INode, sj *shapeJSON) {
+			n := node.(*shapes.MonoSquareNode)
+			sj.DrawStyle = drawStyles[n.DrawStyle()]
+			sj.Centered = n.Centered()
+		},
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoSquareNode(name, style, sj.Centered, world, parent)
+		}))
+
+	s.Register("MonoCircle", &shapes.MonoCircleNode{}, shapeCodec(
+		func(node api.INode, sj *shapeJSON) {
+			n := node.(*shapes.MonoCircleNode)
+			sj.DrawStyle = drawStyles[n.DrawStyle()]
+			sj.Segments = n.Segments()
+		},
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoCircleNode(name, style, sj.segments(), world, parent)
+		}))
+
+	s.Register("MonoArc", &shapes.MonoArcNode{}, shapeCodec(
+		func(node api.INode, sj *shapeJSON) {
+			n := node.(*shapes.MonoArcNode)
+			sj.DrawStyle = drawStyles[n.DrawStyle()]
+			sj.Segments = n.Segments()
+			sj.StartAngle, sj.EndAngle = n.Angles()
+		},
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoArcNode(name, style, sj.segments(), sj.StartAngle, sj.EndAngle, world, parent)
+		}))
+
+	s.Register("MonoTriangle", &shapes.MonoTriangleNode{}, shapeCodec(
+		func(node api.INode, sj *shapeJSON) {
+			sj.DrawStyle = drawStyles[node.(*shapes.MonoTriangleNode).DrawStyle()]
+		},
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoTriangleNode(name, style, world, parent)
+		}))
+
+	s.Register("MonoZBar", &shapes.MonoZBarNode{}, shapeCodec(
+		func(node api.INode, sj *shapeJSON) {
+			sj.DrawStyle = drawStyles[node.(*shapes.MonoZBarNode).DrawStyle()]
+		},
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoZBarNode(name, style, world, parent)
+		}))
+
+	noParameters := func(node api.INode, sj *shapeJSON) {}
+
+	s.Register("MonoHLine", &shapes.MonoHLineNode{}, shapeCodec(noParameters,
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoHLineNode(name, world, parent)
+		}))
+
+	s.Register("MonoVLine", &shapes.MonoVLineNode{}, shapeCodec(noParameters,
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoVLineNode(name, world, parent)
+		}))
+
+	s.Register("MonoPlus", &shapes.MonoPlusNode{}, shapeCodec(noParameters,
+		func(name string, sj *shapeJSON, style int, world api.IWorld, parent api.INode) (api.INode, error) {
+			return shapes.NewMonoPlusNode(name, world, parent)
+		}))
+}
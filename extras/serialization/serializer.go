@@ -0,0 +1,301 @@
+// Package serialization saves node subtrees as JSON and loads them
+// back, so layers and levels can be described as data.
+package serialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+)
+
+// Example:
+//
+//	{
+//	  "Type": "Group",
+//	  "Name": "Game Layer",
+//	  "Children": [
+//	    {
+//	      "Type": "MonoSquare",
+//	      "Name": "Ship",
+//	      "Position": [100, -50],
+//	      "Rotation": 0.785,
+//	      "Scale": [25, 25],
+//	      "Tags": ["player"],
+//	      "Properties": {"DrawStyle": "Filled", "Centered": true, "FilledColor": [1, 0.5, 0, 1]}
+//	    }
+//	  ]
+//	}
+type nodeJSON struct {
+	Type string
+	Name string
+
+	Position []float32 `json:",omitempty"`
+	Rotation float64   `json:",omitempty"`
+	Scale    []float32 `json:",omitempty"`
+
+	// Absent is visible.
+	Visible *bool `json:",omitempty"`
+	// Absent is opaque.
+	Opacity *float32 `json:",omitempty"`
+	// RGBA, 0->1. Absent is white.
+	Tint []float32 `json:",omitempty"`
+
+	ZIndex       int      `json:",omitempty"`
+	SortingLayer string   `json:",omitempty"`
+	Tags         []string `json:",omitempty"`
+
+	Properties json.RawMessage `json:",omitempty"`
+	Children   []*nodeJSON     `json:",omitempty"`
+}
+
+type registration struct {
+	kind  string
+	codec api.INodeCodec
+}
+
+type sceneSerializer struct {
+	kinds map[string]api.INodeCodec
+	types map[reflect.Type]registration
+}
+
+// NewSceneSerializer creates a serializer with the group, nil and mono
+// shape nodes registered.
+func NewSceneSerializer() api.ISceneSerializer {
+	o := new(sceneSerializer)
+	o.kinds = make(map[string]api.INodeCodec)
+	o.types = make(map[reflect.Type]registration)
+
+	registerBuiltIns(o)
+
+	return o
+}
+
+func (s *sceneSerializer) Register(kind string, sample api.INode, codec api.INodeCodec) {
+	s.kinds[kind] = codec
+	s.types[reflect.TypeOf(sample)] = registration{kind: kind, codec: codec}
+}
+
+// --------------------------------------------------------------------------
+// Saving
+// --------------------------------------------------------------------------
+
+func (s *sceneSerializer) Save(node api.INode, w io.Writer) error {
+	nj, err := s.encode(node)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(nj)
+}
+
+func (s *sceneSerializer) encode(node api.INode) (*nodeJSON, error) {
+	reg, ok := s.types[reflect.TypeOf(node)]
+	if !ok {
+		return nil, fmt.Errorf("SceneSerializer: no codec for '%s' (%T)", node.Name(), node)
+	}
+
+	nj := &nodeJSON{
+		Type:         reg.kind,
+		Name:         node.Name(),
+		Rotation:     node.Rotation(),
+		ZIndex:       node.ZIndex(),
+		SortingLayer: node.SortingLayer(),
+	}
+
+	pos := node.Position()
+	if pos.X() != 0.0 || pos.Y() != 0.0 {
+		nj.Position = []float32{pos.X(), pos.Y()}
+	}
+
+	sx, sy := node.ScaleComps()
+	if sx != 1.0 || sy != 1.0 {
+		nj.Scale = []float32{sx, sy}
+	}
+
+	if !node.IsVisible() {
+		visible := false
+		nj.Visible = &visible
+	}
+
+	if node.Opacity() != 1.0 {
+		opacity := node.Opacity()
+		nj.Opacity = &opacity
+	}
+
+	r, g, b, a := node.Tint().Components()
+	if r != 1.0 || g != 1.0 || b != 1.0 || a != 1.0 {
+		nj.Tint = []float32{r, g, b, a}
+	}
+
+	if len(node.Tags()) > 0 {
+		nj.Tags = append([]string{}, node.Tags()...)
+	}
+
+	props, err := reg.codec.Encode(node)
+	if err != nil {
+		return nil, fmt.Errorf("SceneSerializer: '%s': %v", node.Name(), err)
+	}
+	if props != nil {
+		nj.Properties, err = json.Marshal(props)
+		if err != nil {
+			return nil, fmt.Errorf("SceneSerializer: '%s': %v", node.Name(), err)
+		}
+	}
+
+	for _, child := range node.Children() {
+		cj, err := s.encode(child)
+		if err != nil {
+			return nil, err
+		}
+		nj.Children = append(nj.Children, cj)
+	}
+
+	return nj, nil
+}
+
+// --------------------------------------------------------------------------
+// Loading
+// --------------------------------------------------------------------------
+
+func (s *sceneSerializer) Load(r io.Reader, world api.IWorld, parent api.INode) (api.INode, error) {
+	nj := &nodeJSON{}
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(nj)
+	if err != nil {
+		return nil, fmt.Errorf("SceneSerializer: %v", err)
+	}
+
+	var before []api.INode
+	if parent != nil {
+		before = append(before, parent.Children()...)
+	}
+
+	node, err := s.decode(nj, world, parent)
+	if err != nil {
+		discard(node, parent, before)
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// discard destroys what a failed Load built. Constructors attach nodes
+// to the parent before they can fail, so anything new under parent
+// goes too.
+func discard(node, parent api.INode, before []api.INode) {
+	if parent == nil {
+		if node != nil {
+			nodes.DestroySubtree(node)
+		}
+		return
+	}
+
+	added := []api.INode{}
+	for _, child := range parent.Children() {
+		isNew := true
+		for _, b := range before {
+			if b == child {
+				isNew = false
+				break
+			}
+		}
+		if isNew {
+			added = append(added, child)
+		}
+	}
+
+	for _, child := range added {
+		nodes.DestroySubtree(child)
+	}
+}
+
+func (s *sceneSerializer) decode(nj *nodeJSON, world api.IWorld, parent api.INode) (api.INode, error) {
+	codec, ok := s.kinds[nj.Type]
+	if !ok {
+		return nil, fmt.Errorf("SceneSerializer: unknown type '%s' for '%s'", nj.Type, nj.Name)
+	}
+
+	node, err := codec.Decode(nj.Name, nj.Properties, world, parent)
+	if err != nil {
+		return nil, fmt.Errorf("SceneSerializer: '%s': %v", nj.Name, err)
+	}
+
+	// The node is returned with the error so Load can discard it.
+	err = s.apply(nj, node, world)
+	if err != nil {
+		return node, err
+	}
+
+	return node, nil
+}
+
+// apply sets the properties common to all nodes and loads the
+// children. A child the node's constructor already built, found by
+// name, is updated rather than built again.
+func (s *sceneSerializer) apply(nj *nodeJSON, node api.INode, world api.IWorld) error {
+	if len(nj.Position) == 2 {
+		node.SetPosition(nj.Position[0], nj.Position[1])
+	}
+	node.SetRotation(nj.Rotation)
+	if len(nj.Scale) == 2 {
+		node.SetScaleComps(nj.Scale[0], nj.Scale[1])
+	}
+
+	node.SetVisible(nj.Visible == nil || *nj.Visible)
+
+	if nj.Opacity != nil {
+		node.SetOpacity(*nj.Opacity)
+	}
+	if nj.Tint != nil {
+		tint, err := palette(nj.Tint)
+		if err != nil {
+			return fmt.Errorf("SceneSerializer: '%s': %v", nj.Name, err)
+		}
+		node.SetTint(tint)
+	}
+
+	node.SetZIndex(nj.ZIndex)
+	node.SetSortingLayer(nj.SortingLayer)
+	for _, tag := range nj.Tags {
+		node.AddTag(tag)
+	}
+
+	node.SetDirty(true)
+
+	// Only children built by the constructor are matched, each once.
+	built := append([]api.INode{}, node.Children()...)
+
+	for _, cj := range nj.Children {
+		match := -1
+		for i, child := range built {
+			if child != nil && child.Name() == cj.Name {
+				match = i
+				break
+			}
+		}
+
+		if match >= 0 {
+			err := s.apply(cj, built[match], world)
+			if err != nil {
+				return err
+			}
+			built[match] = nil
+			continue
+		}
+
+		_, err := s.decode(cj, world, node)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,8 +3,6 @@ package shapes
 import (
 	"fmt"
 
-	"github.com/go-gl/gl/v4.5-core/gl"
-
 	"github.com/wdevore/Ranger-Go-IGE/api"
 	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
 	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/atlas"
@@ -210,9 +208,9 @@ func (m *DynamicPixelPixelTextNode) Draw(model api.IMatrix4) {
 
 	dpAtlas.Update()
 
-	gl.PointSize(m.pixelSize)
+	dpAtlas.SetPointSize(m.pixelSize)
 
 	atlas.Render(0, model)
 
-	gl.PointSize(1)
+	dpAtlas.SetPointSize(1)
 }
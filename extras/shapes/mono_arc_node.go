@@ -19,6 +19,11 @@ type MonoArcNode struct {
 	filledColor   []float32
 	outlinedColor []float32
 
+	drawStyle  int
+	segments   int
+	startAngle float64
+	endAngle   float64
+
 	vertices []float32
 }
 
@@ -47,6 +52,11 @@ func NewMonoArcNode(name string, drawStyle, segments int, startAngle, endAngle f
 func (b *MonoArcNode) build(drawStyle, segments int, startAngle, endAngle float64, world api.IWorld) error {
 	b.Node.Build(world)
 
+	b.drawStyle = drawStyle
+	b.segments = segments
+	b.startAngle = startAngle
+	b.endAngle = endAngle
+
 	atl := world.GetAtlas(api.MonoAtlasName)
 
 	if atl == nil {
@@ -107,6 +117,34 @@ func (b *MonoArcNode) Vertices() *[]float32 {
 	return &b.vertices
 }
 
+// DrawStyle returns the FILLED, OUTLINED or FILLOUTLINED style it
+// was built with.
+func (b *MonoArcNode) DrawStyle() int {
+	return b.drawStyle
+}
+
+// Segments returns the number of segments it was built with.
+func (b *MonoArcNode) Segments() int {
+	return b.segments
+}
+
+// Angles returns the start and end angles it was built with.
+func (b *MonoArcNode) Angles() (startAngle, endAngle float64) {
+	return b.startAngle, b.endAngle
+}
+
+// FilledColor returns a copy of the fill color
+func (b *MonoArcNode) FilledColor() api.IPalette {
+	c := b.filledColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
+// OutlineColor returns a copy of the outline color
+func (b *MonoArcNode) OutlineColor() api.IPalette {
+	c := b.outlinedColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetFilledColor sets the fill color
 func (b *MonoArcNode) SetFilledColor(color api.IPalette) {
 	b.filledColor = color.Array()
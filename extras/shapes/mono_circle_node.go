@@ -20,6 +20,9 @@ type MonoCircleNode struct {
 
 	filledColor   []float32
 	outlinedColor []float32
+
+	drawStyle int
+	segments  int
 }
 
 // NewMonoCircleNode creates a basic static Circle.
@@ -47,6 +50,9 @@ func NewMonoCircleNode(name string, drawStyle, segments int, world api.IWorld, p
 func (b *MonoCircleNode) build(drawStyle, segments int, world api.IWorld) error {
 	b.Node.Build(world)
 
+	b.drawStyle = drawStyle
+	b.segments = segments
+
 	b.radius = 0.5
 
 	atl := world.GetAtlas(api.MonoAtlasName)
@@ -107,6 +113,29 @@ func (b *MonoCircleNode) Radius() float32 {
 	return float32(b.radius) * b.Scale()
 }
 
+// DrawStyle returns the FILLED, OUTLINED or FILLOUTLINED style it
+// was built with.
+func (b *MonoCircleNode) DrawStyle() int {
+	return b.drawStyle
+}
+
+// Segments returns the number of segments it was built with.
+func (b *MonoCircleNode) Segments() int {
+	return b.segments
+}
+
+// FilledColor returns a copy of the fill color
+func (b *MonoCircleNode) FilledColor() api.IPalette {
+	c := b.filledColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
+// OutlineColor returns a copy of the outline color
+func (b *MonoCircleNode) OutlineColor() api.IPalette {
+	c := b.outlinedColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetFilledColor sets the fill color
 func (b *MonoCircleNode) SetFilledColor(color api.IPalette) {
 	b.filledColor = color.Array()
@@ -74,6 +74,12 @@ func (b *MonoHLineNode) HalfLength() float32 {
 	return b.halfLength * b.Scale()
 }
 
+// Color returns a copy of the color
+func (b *MonoHLineNode) Color() api.IPalette {
+	c := b.color
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetColor sets the color
 func (b *MonoHLineNode) SetColor(color api.IPalette) {
 	b.color = color.Array()
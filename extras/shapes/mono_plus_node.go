@@ -65,6 +65,12 @@ func (b *MonoPlusNode) build(world api.IWorld) error {
 	return nil
 }
 
+// Color returns a copy of the color
+func (b *MonoPlusNode) Color() api.IPalette {
+	c := b.color
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetColor sets the color
 func (b *MonoPlusNode) SetColor(color api.IPalette) {
 	b.color = color.Array()
@@ -22,6 +22,9 @@ type MonoSquareNode struct {
 
 	filledColor   []float32
 	outlinedColor []float32
+
+	drawStyle int
+	centered  bool
 }
 
 // NewMonoSquareNode creates a basic static square.
@@ -49,6 +52,9 @@ func NewMonoSquareNode(name string, drawStyle int, centered bool, world api.IWor
 func (b *MonoSquareNode) build(drawStyle int, centered bool, world api.IWorld) error {
 	b.Node.Build(world)
 
+	b.drawStyle = drawStyle
+	b.centered = centered
+
 	b.halfSide = 0.5
 
 	atl := world.GetAtlas(api.MonoAtlasName)
@@ -139,6 +145,29 @@ func (b *MonoSquareNode) HalfSide() float32 {
 	return b.halfSide * b.Scale()
 }
 
+// DrawStyle returns the FILLED, OUTLINED or FILLOUTLINED style it
+// was built with.
+func (b *MonoSquareNode) DrawStyle() int {
+	return b.drawStyle
+}
+
+// Centered is true if the square's origin is at its center.
+func (b *MonoSquareNode) Centered() bool {
+	return b.centered
+}
+
+// FilledColor returns a copy of the fill color
+func (b *MonoSquareNode) FilledColor() api.IPalette {
+	c := b.filledColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
+// OutlineColor returns a copy of the outline color
+func (b *MonoSquareNode) OutlineColor() api.IPalette {
+	c := b.outlinedColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetFilledColor sets the fill color
 func (b *MonoSquareNode) SetFilledColor(color api.IPalette) {
 	b.filledColor = color.Array()
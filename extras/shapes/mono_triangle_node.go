@@ -22,6 +22,8 @@ type MonoTriangleNode struct {
 
 	filledColor   []float32
 	outlinedColor []float32
+
+	drawStyle int
 }
 
 // NewMonoTriangleNode creates a basic static Triangle.
@@ -49,6 +51,8 @@ func NewMonoTriangleNode(name string, drawStyle int, world api.IWorld, parent ap
 func (b *MonoTriangleNode) build(drawStyle int, world api.IWorld) error {
 	b.Node.Build(world)
 
+	b.drawStyle = drawStyle
+
 	b.halfSide = 0.5
 
 	atl := world.GetAtlas(api.MonoAtlasName)
@@ -127,6 +131,24 @@ func (b *MonoTriangleNode) HalfSide() float32 {
 	return b.halfSide * b.Scale()
 }
 
+// DrawStyle returns the FILLED, OUTLINED or FILLOUTLINED style it
+// was built with.
+func (b *MonoTriangleNode) DrawStyle() int {
+	return b.drawStyle
+}
+
+// FilledColor returns a copy of the fill color
+func (b *MonoTriangleNode) FilledColor() api.IPalette {
+	c := b.filledColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
+// OutlineColor returns a copy of the outline color
+func (b *MonoTriangleNode) OutlineColor() api.IPalette {
+	c := b.outlinedColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetFilledColor sets the fill color
 func (b *MonoTriangleNode) SetFilledColor(color api.IPalette) {
 	b.filledColor = color.Array()
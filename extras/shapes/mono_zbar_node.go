@@ -18,6 +18,8 @@ type MonoZBarNode struct {
 
 	filledColor   []float32
 	outlinedColor []float32
+
+	drawStyle int
 }
 
 // NewMonoZBarNode creates a basic static ZBar.
@@ -45,6 +47,8 @@ func NewMonoZBarNode(name string, drawStyle int, world api.IWorld, parent api.IN
 func (b *MonoZBarNode) build(drawStyle int, world api.IWorld) error {
 	b.Node.Build(world)
 
+	b.drawStyle = drawStyle
+
 	atl := world.GetAtlas(api.MonoAtlasName)
 
 	if atl == nil {
@@ -97,6 +101,24 @@ func (b *MonoZBarNode) build(drawStyle int, world api.IWorld) error {
 	return nil
 }
 
+// DrawStyle returns the FILLED, OUTLINED or FILLOUTLINED style it
+// was built with.
+func (b *MonoZBarNode) DrawStyle() int {
+	return b.drawStyle
+}
+
+// FilledColor returns a copy of the fill color
+func (b *MonoZBarNode) FilledColor() api.IPalette {
+	c := b.filledColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
+// OutlineColor returns a copy of the outline color
+func (b *MonoZBarNode) OutlineColor() api.IPalette {
+	c := b.outlinedColor
+	return color.NewPaletteFromFloats(c[0], c[1], c[2], c[3])
+}
+
 // SetFilledColor sets the fill color
 func (b *MonoZBarNode) SetFilledColor(color api.IPalette) {
 	b.filledColor = color.Array()
@@ -142,10 +142,11 @@ func (z *ZoomNode) Handle(event api.IEvent) bool {
 
 		z.SetFocalPoint(z.zoomPoint.X(), z.zoomPoint.Y())
 	} else if event.GetType() == api.IOTypeMouseWheel {
-		_, dir := event.GetMouseRelMovement()
-		if dir == 1 {
+		// Horizontal scrolling doesn't zoom.
+		_, dy := event.GetScroll()
+		if dy > 0.0 {
 			z.ZoomIn()
-		} else {
+		} else if dy < 0.0 {
 			z.ZoomOut()
 		}
 	}
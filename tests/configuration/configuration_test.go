@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/configuration"
+)
+
+// go test -v -count=1 configuration_test.go
+
+const defaults = "../../engine/configuration/config.json"
+
+func TestRunner(t *testing.T) {
+	testUnknownKeys(t)
+	testLayers(t)
+	testValidation(t)
+	testConstructErrors(t)
+}
+
+func testUnknownKeys(t *testing.T) {
+	props := &configuration.Properties{}
+	err := props.LoadFile(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = props.LoadFile("bad_keys.json")
+	var se *configuration.SourceError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected a SourceError for an unknown key, got: %v", err)
+	}
+}
+
+func testLayers(t *testing.T) {
+	props := &configuration.Properties{}
+	err := props.LoadFile(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = props.ApplyEnv([]string{
+		"HOME=/tmp",
+		"RANGER_ENGINE_UPSRATE=30",
+		"RANGER_WINDOW_DEVICERES_WIDTH=800",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flags are applied last so they win over the environment.
+	err = props.ApplyFlags([]string{
+		"-test.v",
+		"-ranger.Engine.UPSRate=45",
+		"--ranger.window.fullscreen=true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if props.Engine.UPSRate != 45 {
+		t.Errorf("Expected UPSRate 45, got: %f", props.Engine.UPSRate)
+	}
+	if props.Window.DeviceRes.Width != 800 {
+		t.Errorf("Expected DeviceRes.Width 800, got: %d", props.Window.DeviceRes.Width)
+	}
+	if !props.Window.FullScreen {
+		t.Error("Expected FullScreen to be true")
+	}
+
+	// Unrelated variables sharing the prefix are left alone.
+	err = props.ApplyEnv([]string{"RANGER_FOO=1", "RANGER_ENGINE_NOSUCHFIELD=1"})
+	if err != nil {
+		t.Errorf("Expected unknown env variables to be ignored, got: %v", err)
+	}
+
+	err = props.ApplyEnv([]string{"RANGER_ENGINE_UPSRATE=fast"})
+	if err == nil {
+		t.Error("Expected an error for a malformed env value")
+	}
+
+	err = props.ApplyFlags([]string{"-ranger.Engine.UPSRate=fast"})
+	if err == nil {
+		t.Error("Expected an error for a malformed value")
+	}
+}
+
+func testValidation(t *testing.T) {
+	props := &configuration.Properties{}
+	err := props.LoadFile(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = props.Validate(os.DirFS("../.."))
+	if err != nil {
+		t.Fatalf("Expected the defaults to be valid, got: %v", err)
+	}
+
+	err = props.LoadFile("bad_values.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = props.Validate(os.DirFS("../.."))
+	var ve *configuration.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected a ValidationError, got: %v", err)
+	}
+
+	fields := map[string]bool{}
+	for _, fe := range ve.Fields {
+		fields[fe.Field] = true
+	}
+
+	for _, f := range []string{"Engine.UPSRate", "Engine.Backend", "Shaders.MonoVertexShaderFile"} {
+		if !fields[f] {
+			t.Errorf("Expected a FieldError for %s, got: %v", f, ve)
+		}
+	}
+}
+
+func testConstructErrors(t *testing.T) {
+	_, err := engine.ConstructHeadless("../..", "bad_values.json")
+	var ve *configuration.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected Construct to return a ValidationError, got: %v", err)
+	}
+
+	_, err = engine.ConstructHeadless("../..", "no_such_file.json")
+	var se *configuration.SourceError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected Construct to return a SourceError, got: %v", err)
+	}
+}
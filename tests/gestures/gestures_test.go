@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/extras"
+	"github.com/wdevore/Ranger-Go-IGE/extras/gestures"
+)
+
+// go test -v -count=1 gestures_test.go
+
+const ms = int64(1000000)
+
+func TestRunner(t *testing.T) {
+	testTap(t)
+	testLongPress(t)
+	testDragAndFling(t)
+	testPinchRotate(t)
+	testPressElsewhere(t)
+	testReleaseElsewhere(t)
+	testPressAgain(t)
+}
+
+type gameScene struct {
+	nodes.Node
+	nodes.Scene
+}
+
+type layer struct {
+	nodes.Node
+}
+
+// card forwards to its detector.
+type card struct {
+	nodes.Node
+	detector api.IGestureDetector
+	// handled is what the detector last returned
+	handled bool
+}
+
+func (c *card) ReceivesEvents() bool {
+	return true
+}
+
+func (c *card) Handle(event api.IEvent) bool {
+	c.handled = c.detector.Handle(event)
+	return c.handled
+}
+
+// catcher handles any button release over it.
+type catcher struct {
+	nodes.Node
+	releases int
+}
+
+func (c *catcher) ReceivesEvents() bool {
+	return true
+}
+
+func (c *catcher) Handle(event api.IEvent) bool {
+	if event.GetType() == api.IOTypeMouseButtonUp {
+		c.releases++
+		return true
+	}
+	return false
+}
+
+type fixture struct {
+	t *testing.T
+
+	world api.IWorld
+	layer api.INode
+	card  *card
+
+	// marker is moved to where each event is sent.
+	marker api.INode
+	point  api.IPoint
+
+	detector api.IGestureDetector
+	gestures []string
+	last     api.IGesture
+}
+
+var kindNames = []string{"tap", "long", "drag", "fling", "pinch", "rotate"}
+var phaseNames = []string{"began", "changed", "ended", "cancelled"}
+
+// newFixture puts a card, at (100, 0) in a layer, under a detector
+// recognizing kinds. The card is 500 units square. The layer's scene
+// is on stage so events are routed by the NodeManager.
+func newFixture(t *testing.T, kinds ...int) *fixture {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(eng.End)
+
+	f := &fixture{t: t, world: eng.World()}
+
+	scene := new(gameScene)
+	scene.Initialize("Game")
+	scene.Node.Build(f.world)
+
+	l := new(layer)
+	l.Initialize("Layer")
+	l.SetParent(scene)
+	scene.AddChild(l)
+	l.Build(f.world)
+	f.layer = l
+
+	f.card = new(card)
+	f.card.Initialize("Card")
+	f.card.SetParent(f.layer)
+	f.layer.AddChild(f.card)
+	f.card.Build(f.world)
+	f.card.SetPosition(100.0, 0.0)
+	f.card.SetBoundBySize(500.0, 500.0)
+
+	f.marker, _ = extras.NewNilNode("Marker")
+	f.marker.SetParent(f.layer)
+	f.point = geometry.NewPoint()
+
+	f.detector = gestures.NewGestureDetector(f.card)
+	f.card.detector = f.detector
+	for _, kind := range kinds {
+		f.detector.Recognize(kind)
+	}
+	f.detector.SetListener(func(g api.IGesture) {
+		f.gestures = append(f.gestures, kindNames[g.Kind()]+" "+phaseNames[g.Phase()])
+		f.last = g
+	})
+
+	f.world.Push(scene)
+	f.world.Push(extras.NewBasicBootScene("Boot"))
+
+	// Transition from Boot to the scene.
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return f
+}
+
+// send routes a pointer event at a layer-space position and returns
+// whether the card's detector handled it.
+func (f *fixture) send(eventType uint32, x, y float32, time int64, mods int, clicks uint8) bool {
+	f.marker.SetPosition(x, y)
+	nodes.MapNodeToDevice(f.world, f.marker, f.point)
+
+	event := io.NewEvent()
+	event.SetType(eventType)
+	event.SetButton(api.MouseButtonLeft)
+	if eventType == api.IOTypeMouseButtonDown {
+		event.SetState(api.KeyPress)
+	}
+	event.SetMousePosition(int32(math.Round(float64(f.point.X()))), int32(math.Round(float64(f.point.Y()))))
+	event.SetTimestamp(time)
+	event.SetKeyMotif(uint32(mods))
+	event.SetClicks(clicks)
+
+	f.card.handled = false
+	f.world.RouteEvents(event)
+
+	return f.card.handled
+}
+
+func (f *fixture) expect(expected ...string) {
+	f.t.Helper()
+	if fmt.Sprint(f.gestures) != fmt.Sprint(expected) {
+		f.t.Errorf("Expected %v, got %v", expected, f.gestures)
+	}
+	f.gestures = nil
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.05
+}
+
+func testTap(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureLongPress, api.GestureDrag)
+
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 0, 0, 1)
+	f.detector.Update(100.0)
+	f.send(api.IOTypeMouseButtonUp, 101.0, 0.0, 100*ms, 0, 1)
+	f.expect("tap ended")
+
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 200*ms, 0, 2)
+	f.send(api.IOTypeMouseButtonUp, 100.0, 0.0, 250*ms, 0, 2)
+	f.expect("tap ended")
+	if f.last.Taps() != 2 {
+		t.Errorf("Expected a double tap, got %d", f.last.Taps())
+	}
+}
+
+func testLongPress(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureLongPress, api.GestureDrag)
+
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 0, 0, 1)
+	f.detector.Update(300.0)
+	f.expect()
+	f.detector.Update(300.0)
+	f.expect("long began")
+
+	// A long press holds off the drag.
+	f.send(api.IOTypeMouseMotion, 150.0, 0.0, 700*ms, 0, 0)
+	f.send(api.IOTypeMouseButtonUp, 150.0, 0.0, 800*ms, 0, 1)
+	f.expect("long changed", "long ended")
+
+	// Moving first rules out a long press.
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 1000*ms, 0, 1)
+	f.send(api.IOTypeMouseMotion, 130.0, 0.0, 1010*ms, 0, 0)
+	f.detector.Update(600.0)
+	f.send(api.IOTypeMouseButtonUp, 130.0, 0.0, 1600*ms, 0, 1)
+	f.expect("drag began", "drag ended")
+}
+
+func testDragAndFling(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureDrag, api.GestureFling)
+
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 0, 0, 1)
+	f.send(api.IOTypeMouseMotion, 120.0, 0.0, 20*ms, 0, 0)
+	if !near(float64(f.last.Delta().X()), 20.0) {
+		t.Errorf("Expected the drag to begin with the movement from the press, got %v", f.last.Delta())
+	}
+	f.send(api.IOTypeMouseMotion, 160.0, 0.0, 60*ms, 0, 0)
+	if !near(float64(f.last.Delta().X()), 40.0) || !near(float64(f.last.Position().X()), 160.0) {
+		t.Errorf("Expected a delta of 40 to 160, got %v to %v", f.last.Delta(), f.last.Position())
+	}
+	f.send(api.IOTypeMouseButtonUp, 160.0, 0.0, 60*ms, 0, 1)
+	f.expect("drag began", "drag changed", "drag ended", "fling ended")
+
+	if f.last.Direction() != api.SwipeRight {
+		t.Errorf("Expected a fling to the right, got %d", f.last.Direction())
+	}
+	if !near(float64(f.last.Velocity().X()), 1000.0) {
+		t.Errorf("Expected 1000 units/s, got %v", f.last.Velocity())
+	}
+
+	// Too slow to fling
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 1000*ms, 0, 1)
+	f.send(api.IOTypeMouseMotion, 100.0, -20.0, 1500*ms, 0, 0)
+	f.send(api.IOTypeMouseButtonUp, 100.0, -20.0, 2000*ms, 0, 1)
+	f.expect("drag began", "drag ended")
+}
+
+func testPinchRotate(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureDrag, api.GesturePinch, api.GestureRotate)
+
+	angles := map[string]float64{}
+	scales := map[string]float32{}
+	f.detector.SetListener(func(g api.IGesture) {
+		name := kindNames[g.Kind()] + " " + phaseNames[g.Phase()]
+		f.gestures = append(f.gestures, name)
+		angles[name] = g.Rotation()
+		scales[name] = g.Scale()
+	})
+
+	// 100 units right of the card's origin
+	f.send(api.IOTypeMouseButtonDown, 200.0, 0.0, 0, api.ModControl, 1)
+	// Twice as far away
+	f.send(api.IOTypeMouseMotion, 300.0, 0.0, 10*ms, api.ModControl, 0)
+	// A quarter turn anticlockwise at the original distance
+	f.send(api.IOTypeMouseMotion, 100.0, 100.0, 20*ms, api.ModControl, 0)
+	f.send(api.IOTypeMouseButtonUp, 100.0, 100.0, 30*ms, api.ModControl, 1)
+	f.expect("pinch began", "rotate began", "pinch changed", "rotate changed", "pinch ended", "rotate ended")
+
+	if !near(float64(scales["pinch began"]), 2.0) || !near(float64(scales["pinch ended"]), 1.0) {
+		t.Errorf("Expected scales of 2 then 1, got %v", scales)
+	}
+	if !near(angles["rotate began"], 0.0) || !near(angles["rotate ended"], math.Pi/2.0) {
+		t.Errorf("Expected rotations of 0 then Pi/2, got %v", angles)
+	}
+
+	// Without the modifier it's a drag.
+	f.send(api.IOTypeMouseButtonDown, 200.0, 0.0, 1000*ms, 0, 1)
+	f.send(api.IOTypeMouseMotion, 300.0, 0.0, 1010*ms, 0, 0)
+	f.detector.Cancel()
+	f.expect("drag began", "drag cancelled")
+}
+
+func testPressElsewhere(t *testing.T) {
+	f := newFixture(t, api.GestureTap)
+
+	if f.send(api.IOTypeMouseButtonDown, -300.0, 0.0, 0, 0, 1) {
+		t.Error("Expected a press off the card to be ignored")
+	}
+	if f.send(api.IOTypeMouseButtonUp, 100.0, 0.0, 0, 0, 1) {
+		t.Error("Expected a release without a press to be ignored")
+	}
+	f.expect()
+}
+
+func testReleaseElsewhere(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureDrag)
+
+	other := new(catcher)
+	other.Initialize("Other")
+	other.SetParent(f.layer)
+	f.layer.AddChild(other)
+	other.SetPosition(400.0, 0.0)
+	other.SetBoundBySize(80.0, 80.0)
+
+	// The card captured the press so the release over the other node
+	// still ends the drag.
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 0, 0, 1)
+	f.send(api.IOTypeMouseMotion, 400.0, 0.0, 20*ms, 0, 0)
+	if !f.send(api.IOTypeMouseButtonUp, 400.0, 0.0, 40*ms, 0, 1) {
+		t.Error("Expected the card to get the release")
+	}
+	f.expect("drag began", "drag ended")
+	if other.releases != 0 {
+		t.Errorf("Expected the other node not to see the release, got %d", other.releases)
+	}
+
+	// The capture ended with the release.
+	f.send(api.IOTypeMouseButtonUp, 400.0, 0.0, 60*ms, 0, 1)
+	if other.releases != 1 {
+		t.Errorf("Expected the other node to get the next release, got %d", other.releases)
+	}
+
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 100*ms, 0, 1)
+	f.send(api.IOTypeMouseButtonUp, 100.0, 0.0, 150*ms, 0, 1)
+	f.expect("tap ended")
+}
+
+func testPressAgain(t *testing.T) {
+	f := newFixture(t, api.GestureTap, api.GestureLongPress, api.GestureDrag)
+
+	// A second press, as if the release was lost, starts over.
+	f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 0, 0, 1)
+	f.send(api.IOTypeMouseMotion, 130.0, 0.0, 10*ms, 0, 0)
+	if !f.send(api.IOTypeMouseButtonDown, 100.0, 0.0, 100*ms, 0, 1) {
+		t.Error("Expected the second press to be tracked")
+	}
+	f.send(api.IOTypeMouseButtonUp, 100.0, 0.0, 150*ms, 0, 1)
+	f.expect("drag began", "drag cancelled", "tap ended")
+}
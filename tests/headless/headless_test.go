@@ -0,0 +1,1358 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/atlas"
+	"github.com/wdevore/Ranger-Go-IGE/engine/timing"
+	"github.com/wdevore/Ranger-Go-IGE/extras"
+	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
+)
+
+// go test -v -count=1 headless_test.go
+
+func TestRunner(t *testing.T) {
+	testHeadlessAtlases(t)
+	testHeadlessUpdateVisit(t)
+	testStepping(t)
+	testDebugController(t)
+	testProfiler(t)
+	testRunLimit(t)
+	testAssets(t)
+	testMemoryFS(t)
+	testInputPlayback(t)
+	testQueuedInput(t)
+	testPointerDispatch(t)
+	testFocus(t)
+	testFocusWithKeyTarget(t)
+	testDrawOrder(t)
+	testChildManagement(t)
+	testQueries(t)
+}
+
+type countingScene struct {
+	nodes.Node
+	nodes.Scene
+
+	updates int
+	// Updates on which the "fire" action was pressed
+	fired []int
+}
+
+func newCountingScene(name string, world api.IWorld) (*countingScene, error) {
+	o := new(countingScene)
+	o.Initialize(name)
+	o.Node.Build(world)
+
+	_, err := shapes.NewMonoSquareNode("Square", api.FILLED, true, world, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (s *countingScene) Update(msPerUpdate, secPerUpdate float64) {
+	s.updates++
+	if s.World().Input().Pressed("fire") {
+		s.fired = append(s.fired, s.updates)
+	}
+}
+
+func (s *countingScene) EnterScene(man api.INodeManager) {
+	man.RegisterTarget(s)
+}
+
+func (s *countingScene) ExitScene(man api.INodeManager) bool {
+	man.UnRegisterTarget(s)
+	return false
+}
+
+func testHeadlessAtlases(t *testing.T) {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.End()
+
+	world := eng.World()
+
+	if world.Properties().Engine.Backend != api.BackendHeadless {
+		t.Errorf("expected %s backend, got %s", api.BackendHeadless, world.Properties().Engine.Backend)
+	}
+
+	mono := atlas.NewStaticMonoAtlas(world)
+	if _, ok := mono.(api.IStaticAtlasX); !ok {
+		t.Error("headless static atlas doesn't implement IStaticAtlasX")
+	}
+
+	if _, ok := atlas.NewDynamicMonoAtlas(world).(api.IDynamicAtlasX); !ok {
+		t.Error("headless dynamic atlas doesn't implement IDynamicAtlasX")
+	}
+
+	if _, ok := atlas.NewDynamicPixelAtlas(world).(api.IDynamicPixelAtlasX); !ok {
+		t.Error("headless pixel atlas doesn't implement IDynamicPixelAtlasX")
+	}
+
+	if _, ok := atlas.NewSingleTextureAtlas("Tex", nil, world).(api.ISingleTextureAtlasX); !ok {
+		t.Error("headless texture atlas doesn't implement ISingleTextureAtlasX")
+	}
+
+	err = mono.Burn()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// buildCountingGame constructs a headless engine with a Boot scene
+// followed by a countingScene.
+func buildCountingGame(t *testing.T) (api.IEngine, *countingScene) {
+	return buildCountingGameWith(t, "")
+}
+
+func buildCountingGameWith(t *testing.T, overrides string) (api.IEngine, *countingScene) {
+	eng, err := engine.ConstructHeadless("../..", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	world := eng.World()
+
+	monoAtlas := world.GetAtlas(api.MonoAtlasName)
+	if monoAtlas == nil {
+		monoAtlas = atlas.NewStaticMonoAtlas(world)
+		world.AddAtlas(api.MonoAtlasName, monoAtlas)
+	}
+
+	scene, err := newCountingScene("Counting", world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.Push(scene)
+	world.Push(extras.NewBasicBootScene("Boot"))
+
+	err = monoAtlas.Burn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return eng, scene
+}
+
+func testHeadlessUpdateVisit(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+
+	world := eng.World()
+
+	sceneGraph := world.NodeManager()
+	err := sceneGraph.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		sceneGraph.Update(16.0, 0.016)
+		if !sceneGraph.Visit(0.0) {
+			t.Fatal("expected scenes to still be visitable")
+		}
+	}
+
+	if scene.updates == 0 {
+		t.Error("expected the scene to have been updated")
+	}
+}
+
+func testStepping(t *testing.T) {
+	run := func() (*countingScene, api.IEngine) {
+		eng, scene := buildCountingGame(t)
+
+		clock := timing.NewManualClock()
+		eng.SetClock(clock)
+
+		// Let the Boot scene exit and the counting scene enter.
+		for i := 0; i < 5; i++ {
+			more, err := eng.Step(0, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !more {
+				t.Fatal("expected more scenes")
+			}
+		}
+
+		_, err := eng.Step(10, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = eng.Step(5, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return scene, eng
+	}
+
+	sceneA, engA := run()
+	defer engA.End()
+	sceneB, engB := run()
+	defer engB.End()
+
+	if engA.Ticks() != 15 {
+		t.Errorf("expected 15 ticks, got %d", engA.Ticks())
+	}
+
+	if sceneA.updates != 15 || sceneA.updates != sceneB.updates {
+		t.Errorf("expected 15 updates from both runs, got %d and %d", sceneA.updates, sceneB.updates)
+	}
+
+	// At 60 UPS an update period is 16.666667ms.
+	expected := int64(15 * 16666667)
+	if engA.Clock().Now() != expected {
+		t.Errorf("expected manual clock at %d, got %d", expected, engA.Clock().Now())
+	}
+}
+
+func testDebugController(t *testing.T) {
+	dc := timing.NewDebugController()
+
+	dc.RequestStep()
+	if dc.TakeStep() {
+		t.Fatal("Expected step request to be ignored while running")
+	}
+
+	dc.TogglePause()
+	dc.RequestStep()
+	if !dc.TakeStep() || dc.TakeStep() {
+		t.Fatal("Expected exactly one pending step while paused")
+	}
+
+	for i := 0; i < 10; i++ {
+		dc.ScaleUp()
+	}
+	if dc.TimeScale() != 16.0 {
+		t.Fatalf("Expected time scale clamped to 16, got %f", dc.TimeScale())
+	}
+
+	dc.ResetTimeScale()
+	dc.ScaleDown()
+	if dc.TimeScale() != 0.5 {
+		t.Fatalf("Expected time scale 0.5, got %f", dc.TimeScale())
+	}
+}
+
+func testProfiler(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+
+	_, err := shapes.NewMonoSquareNode("Square", api.FILLED, true, eng.World(), scene)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiler := eng.Profiler()
+	profiler.SetEnabled(true)
+
+	// Another engine's draws aren't counted, nor are these counted by it.
+	other, _ := buildCountingGame(t)
+	defer other.End()
+	_, err = other.Step(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 8; i++ {
+		_, err = eng.Step(1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if profiler.FrameCount() != 8 {
+		t.Fatalf("expected 8 profiled frames, got %d", profiler.FrameCount())
+	}
+
+	calls, _ := profiler.DrawStats(7, "*shapes.MonoSquareNode")
+	if calls == 0 {
+		t.Error("expected square draw calls in the last frame")
+	}
+
+	var buf bytes.Buffer
+	err = profiler.WriteChromeTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trace struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trace.TraceEvents) == 0 {
+		t.Error("expected trace events")
+	}
+}
+
+// frameClock advances a fixed amount every time it is read which lets
+// Begin() make progress without depending on wall time.
+type frameClock struct {
+	now int64
+}
+
+func (c *frameClock) Now() int64 {
+	c.now += 16666667
+	return c.now
+}
+
+func testRunLimit(t *testing.T) {
+	eng, scene := buildCountingGameWith(t, "loop_updates.json")
+	eng.SetClock(new(frameClock))
+
+	err := eng.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if eng.Ticks() < 30 || eng.Ticks() > 31 {
+		t.Errorf("expected the loop to stop after 30 updates, got %d", eng.Ticks())
+	}
+	if scene.updates == 0 {
+		t.Error("expected the scene to have been updated")
+	}
+	if eng.ExitCode() != 7 {
+		t.Errorf("expected exit code 7, got %d", eng.ExitCode())
+	}
+
+	eng.End()
+}
+
+func testAssets(t *testing.T) {
+	eng, _ := buildCountingGame(t)
+	defer eng.End()
+
+	world := eng.World()
+	assets := world.Assets()
+
+	if assets.RefCount(api.AssetRasterFont, "RasterFont") != 1 {
+		t.Error("Expected the World's raster font to be managed")
+	}
+	if world.GetAtlas(api.MonoAtlasName) != assets.Get(api.AssetAtlas, api.MonoAtlasName) {
+		t.Error("Expected atlases to be managed")
+	}
+
+	const manifest = "examples/assets/font9x9_sprite_sheet_manifest.json"
+	assets.Preload(api.AssetSpriteSheet, "Font9x9", manifest)
+	assets.Preload(api.AssetSound, "Sonar", "extras/sfxr/AlienSonar_sfxr.json")
+	assets.Preload(api.AssetSound, "Missing", "no_such_sound.json")
+
+	if done, total := assets.Progress(); done != 0 || total != 3 {
+		t.Errorf("Expected progress 0/3, got: %d/%d", done, total)
+	}
+
+	// Decoding happens in the background and uploading between frames.
+	for i := 0; !assets.Loaded() && i < 1000; i++ {
+		_, err := eng.Step(0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if done, total := assets.Progress(); done != 3 || total != 3 {
+		t.Fatalf("Expected progress 3/3, got: %d/%d", done, total)
+	}
+
+	if _, ok := assets.Get(api.AssetSpriteSheet, "Font9x9").(api.ISpriteSheet); !ok {
+		t.Error("Expected the sprite sheet to be preloaded")
+	}
+	if _, ok := assets.Get(api.AssetSound, "Sonar").(api.IGeneratorValues); !ok {
+		t.Error("Expected the sound to be preloaded")
+	}
+	if assets.Get(api.AssetSound, "Missing") != nil {
+		t.Error("Expected the missing sound to fail")
+	}
+
+	// A second Load shares the preloaded sheet.
+	sheet, err := assets.Load(api.AssetSpriteSheet, "Font9x9", manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet != assets.Get(api.AssetSpriteSheet, "Font9x9") {
+		t.Error("Expected Load to return the preloaded sheet")
+	}
+	if n := assets.RefCount(api.AssetSpriteSheet, "Font9x9"); n != 2 {
+		t.Errorf("Expected 2 references, got: %d", n)
+	}
+
+	assets.Release(api.AssetSpriteSheet, "Font9x9")
+	assets.Release(api.AssetSpriteSheet, "Font9x9")
+	if assets.Get(api.AssetSpriteSheet, "Font9x9") != nil {
+		t.Error("Expected the sheet to be released")
+	}
+
+	_, err = assets.Load("Model", "Ship", "ship.obj")
+	if err == nil {
+		t.Error("Expected an error for a type without a loader")
+	}
+}
+
+// memoryFS copies just what the engine needs into an in-memory FS.
+func memoryFS(t *testing.T) fstest.MapFS {
+	disk := os.DirFS("../..")
+	memory := fstest.MapFS{}
+
+	copyFile := func(name string) {
+		data, err := fs.ReadFile(disk, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		memory[name] = &fstest.MapFile{Data: data}
+	}
+
+	copyFile(api.ConfigFile)
+	copyFile("assets/raster_font.data")
+	copyFile("examples/assets/font9x9_sprite_sheet_manifest.json")
+	copyFile("examples/assets/font9x9_sprite_sheet.png")
+
+	shaders, err := fs.ReadDir(disk, api.ShaderDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shader := range shaders {
+		copyFile(api.ShaderDir + "/" + shader.Name())
+	}
+
+	memory["game/config.json"] = &fstest.MapFile{Data: []byte(`{"Window": {"Title": "Memory"}}`)}
+
+	return memory
+}
+
+func testMemoryFS(t *testing.T) {
+	memory := memoryFS(t)
+
+	// Nothing is read relative to the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	eng, err := engine.ConstructFS(memory, "game/config.json", api.BackendHeadless)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.End()
+
+	world := eng.World()
+
+	if title := world.Properties().Window.Title; title != "Memory" {
+		t.Errorf("Expected the overrides to be read from the FS, got title: %s", title)
+	}
+	if world.RasterFont().GlyphWidth() == 0 {
+		t.Error("Expected the raster font to be read from the FS")
+	}
+
+	_, err = world.Assets().Load(api.AssetSpriteSheet, "Font9x9", "examples/assets/font9x9_sprite_sheet_manifest.json")
+	if err != nil {
+		t.Errorf("Expected the sprite sheet to be read from the FS, got: %v", err)
+	}
+
+	memory["game/level.json"] = &fstest.MapFile{Data: []byte(`{"Window": {"Title": "Level"}}`)}
+	err = world.PropertiesOverride("game/level.json")
+	if err != nil {
+		t.Errorf("Expected the override to be read from the FS, got: %v", err)
+	}
+	if title := world.Properties().Window.Title; title != "Level" {
+		t.Errorf("Expected the override title, got: %s", title)
+	}
+
+	delete(memory, api.ConfigFile)
+	_, err = engine.ConstructFS(memory, "", api.BackendHeadless)
+	if err == nil {
+		t.Error("Expected an error without the engine's config")
+	}
+}
+
+func testInputPlayback(t *testing.T) {
+	const keyZ = 90
+
+	key := func(state int) api.IEvent {
+		event := io.NewEvent()
+		event.SetType(api.IOTypeKeyboard)
+		event.SetKeyCode(keyZ)
+		event.SetState(uint32(state))
+		return event
+	}
+
+	start := func() (api.IEngine, *countingScene) {
+		eng, scene := buildCountingGame(t)
+		eng.World().Input().BindKey("fire", keyZ, 0)
+		for i := 0; i < 6; i++ {
+			_, err := eng.Step(0, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return eng, scene
+	}
+
+	step := func(eng api.IEngine, updates int) {
+		_, err := eng.Step(updates, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Record
+	eng, scene := start()
+	defer eng.End()
+	world := eng.World()
+	recorder := world.Recorder()
+
+	recorder.Record()
+	step(eng, 2)
+	world.RouteEvents(key(api.KeyPress))
+	step(eng, 3)
+	world.RouteEvents(key(api.KeyRelease))
+	step(eng, 1)
+	// A tap between two updates
+	world.RouteEvents(key(api.KeyPress))
+	world.RouteEvents(key(api.KeyRelease))
+	step(eng, 4)
+	recorder.Stop()
+
+	if len(scene.fired) != 2 || scene.fired[0] != 3 || scene.fired[1] != 7 {
+		t.Fatalf("Expected fire on updates 3 and 7, got %v", scene.fired)
+	}
+
+	var recording bytes.Buffer
+	err := recorder.Save(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Play back in one go. The events arrive at the same ticks even
+	// though the updates are grouped differently.
+	engB, sceneB := start()
+	defer engB.End()
+
+	err = engB.World().Recorder().Play(bytes.NewReader(recording.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step(engB, 12)
+
+	if fmt.Sprint(sceneB.fired) != fmt.Sprint(scene.fired) {
+		t.Errorf("Expected playback to fire on %v, got %v", scene.fired, sceneB.fired)
+	}
+	if engB.World().Recorder().Playing() {
+		t.Error("Expected playback to have finished")
+	}
+
+	err = engB.World().Recorder().Play(bytes.NewReader(recording.Bytes()[:recording.Len()-3]))
+	if err == nil {
+		t.Error("Expected an error playing a truncated recording")
+	}
+}
+
+// testQueuedInput posts events to the queue, like the display does,
+// and checks Step routes them before its updates.
+func testQueuedInput(t *testing.T) {
+	const keyZ = 90
+
+	key := func(state int) api.IEvent {
+		event := io.NewEvent()
+		event.SetType(api.IOTypeKeyboard)
+		event.SetKeyCode(keyZ)
+		event.SetState(uint32(state))
+		return event
+	}
+
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	world := eng.World()
+	world.Input().BindKey("fire", keyZ, 0)
+
+	step := func(updates int, visit bool) {
+		_, err := eng.Step(updates, visit)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		step(0, true)
+	}
+
+	queue := world.EventQueue()
+
+	step(2, false)
+	queue.Post(key(api.KeyPress))
+	step(3, false)
+	queue.Post(key(api.KeyRelease))
+	step(1, false)
+	// A tap between two updates
+	queue.Post(key(api.KeyPress))
+	queue.Post(key(api.KeyRelease))
+	step(4, false)
+
+	if fmt.Sprint(scene.fired) != "[3 7]" {
+		t.Errorf("Expected queued fire on updates 3 and 7, got %v", scene.fired)
+	}
+	if queue.Len() != 0 {
+		t.Errorf("Expected the queue to be drained, got %d events", queue.Len())
+	}
+}
+
+// pointerNode logs the events it sees as "name phase type".
+type pointerNode struct {
+	nodes.Node
+
+	log *[]string
+	// Handle returns true for this event type
+	stopOn uint32
+	// deaf nodes don't receive routed events
+	deaf bool
+}
+
+var eventNames = map[uint32]string{
+	api.IOTypeKeyboard:          "key",
+	api.IOTypeGamepadButtonDown: "pad",
+	api.IOTypeMouseMotion:       "motion",
+	api.IOTypeMouseButtonDown:   "down",
+	api.IOTypeMouseButtonUp:     "up",
+	api.IOTypePointerEnter:      "enter",
+	api.IOTypePointerLeave:      "leave",
+	api.IOTypePointerHover:      "hover",
+}
+
+var phaseNames = []string{"none", "capture", "target", "bubble"}
+
+func newPointerNode(name string, log *[]string, parent api.INode) *pointerNode {
+	o := new(pointerNode)
+	o.Initialize(name)
+	o.SetParent(parent)
+	parent.AddChild(o)
+	o.log = log
+	return o
+}
+
+func (p *pointerNode) ReceivesEvents() bool {
+	return !p.deaf
+}
+
+func (p *pointerNode) Handle(event api.IEvent) bool {
+	*p.log = append(*p.log, p.Name()+" "+phaseNames[event.GetPhase()]+" "+eventNames[event.GetType()])
+	return p.stopOn != 0 && event.GetType() == p.stopOn
+}
+
+type capturingNode struct {
+	pointerNode
+}
+
+func (c *capturingNode) HandleCapture(event api.IEvent) bool {
+	return c.Handle(event)
+}
+
+type triangleNode struct {
+	pointerNode
+	polygon api.IPolygon
+}
+
+func (n *triangleNode) HitPolygon() api.IPolygon {
+	return n.polygon
+}
+
+// devicePoint returns the device position of a point in node's space.
+func devicePoint(world api.IWorld, node api.INode, x, y float32) (int32, int32) {
+	marker, _ := extras.NewNilNode("marker")
+	marker.SetParent(node)
+	marker.SetPosition(x, y)
+	p := geometry.NewPoint()
+	nodes.MapNodeToDevice(world, marker, p)
+	return int32(p.X()), int32(p.Y())
+}
+
+func testPointerDispatch(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := eng.World()
+	log := []string{}
+
+	panel := new(capturingNode)
+	panel.Initialize("panel")
+	panel.SetParent(scene)
+	scene.AddChild(panel)
+	panel.log = &log
+	panel.SetPosition(50.0, 50.0)
+	panel.SetBoundBySize(60.0, 60.0)
+
+	button := newPointerNode("button", &log, panel)
+	button.SetPosition(10.0, 10.0)
+	button.SetBoundBySize(10.0, 10.0)
+	button.stopOn = api.IOTypeMouseButtonDown
+
+	tri := new(triangleNode)
+	tri.Initialize("tri")
+	tri.SetParent(scene)
+	scene.AddChild(tri)
+	tri.log = &log
+	tri.SetPosition(-60.0, 0.0)
+	tri.polygon = geometry.NewPolygon()
+	tri.polygon.AddVertex(-10.0, -10.0)
+	tri.polygon.AddVertex(10.0, -10.0)
+	tri.polygon.AddVertex(0.0, 10.0)
+
+	// Unhit nodes get whatever isn't handled, as before.
+	legacy := newPointerNode("legacy", &log, scene)
+	world.NodeManager().RegisterEventTarget(legacy)
+
+	device := func(node api.INode, x, y float32) (int32, int32) {
+		return devicePoint(world, node, x, y)
+	}
+
+	send := func(eventType uint32, x, y int32, expected ...string) {
+		t.Helper()
+		log = log[:0]
+		event := io.NewEvent()
+		event.SetType(eventType)
+		event.SetMousePosition(x, y)
+		world.RouteEvents(event)
+		if fmt.Sprint(log) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, log)
+		}
+	}
+
+	bx, by := device(button, 0.0, 0.0)
+	send(api.IOTypeMouseMotion, bx, by,
+		"panel target enter", "button target enter", "button target hover",
+		"panel capture motion", "button target motion", "panel bubble motion",
+		"legacy none motion")
+
+	if world.NodeManager().HitTest(bx, by) != button {
+		t.Error("Expected the button to be hit")
+	}
+
+	// The button stops the press and so captures the pointer.
+	send(api.IOTypeMouseButtonDown, bx, by,
+		"panel capture down", "button target down")
+
+	// Moving off the button but still over the panel
+	px, py := device(panel, -20.0, -20.0)
+	send(api.IOTypeMouseMotion, px, py,
+		"button target leave", "panel target hover",
+		"button target motion", "legacy none motion")
+	send(api.IOTypeMouseButtonUp, px, py,
+		"button target up", "legacy none up")
+
+	// Released
+	send(api.IOTypeMouseMotion, px, py,
+		"panel target hover", "panel target motion", "legacy none motion")
+
+	fx, fy := device(scene, -200.0, 200.0)
+	send(api.IOTypeMouseMotion, fx, fy,
+		"panel target leave", "legacy none motion")
+
+	tx, ty := device(tri, 0.0, 0.0)
+	send(api.IOTypeMouseMotion, tx, ty,
+		"tri target enter", "tri target hover", "tri target motion", "legacy none motion")
+
+	// Inside the polygon's bounding box but outside the triangle
+	tx, ty = device(tri, 8.0, 8.0)
+	send(api.IOTypeMouseMotion, tx, ty,
+		"tri target leave", "legacy none motion")
+
+	// Hidden nodes can't be hit.
+	panel.SetVisible(false)
+	send(api.IOTypeMouseMotion, bx, by, "legacy none motion")
+	if world.NodeManager().HitTest(bx, by) != nil {
+		t.Error("Expected nothing to be hit")
+	}
+
+	// A node that doesn't receive is still hit but is passed over.
+	panel.SetVisible(true)
+	button.deaf = true
+	send(api.IOTypeMouseMotion, bx, by,
+		"panel target enter", "panel capture motion", "panel bubble motion",
+		"legacy none motion")
+
+	// Plain nodes never receive.
+	plain, _ := extras.NewGroupNode("plain", world, scene)
+	plain.SetPosition(-60.0, -60.0)
+	plain.SetBoundBySize(10.0, 10.0)
+	gx, gy := device(plain, 0.0, 0.0)
+	send(api.IOTypeMouseMotion, gx, gy,
+		"panel target leave", "legacy none motion")
+	if world.NodeManager().HitTest(gx, gy) != plain {
+		t.Error("Expected the plain node to be hit")
+	}
+}
+
+type focusableNode struct {
+	pointerNode
+	disabled bool
+	// Handle returns true for key events
+	takesKeys bool
+}
+
+func newFocusableNode(name string, log *[]string, parent api.INode, x, y float32) *focusableNode {
+	o := new(focusableNode)
+	o.Initialize(name)
+	o.SetParent(parent)
+	parent.AddChild(o)
+	o.log = log
+	o.SetPosition(x, y)
+	return o
+}
+
+func (f *focusableNode) Handle(event api.IEvent) bool {
+	handled := f.pointerNode.Handle(event)
+	return handled || f.takesKeys && event.GetType() == api.IOTypeKeyboard
+}
+
+func (f *focusableNode) Focusable() bool {
+	return !f.disabled
+}
+
+func (f *focusableNode) Focus() {
+	*f.log = append(*f.log, f.Name()+" focus")
+}
+
+func (f *focusableNode) Blur() {
+	*f.log = append(*f.log, f.Name()+" blur")
+}
+
+func testFocus(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := eng.World()
+	focus := world.NodeManager().Focus()
+	log := []string{}
+
+	panel := newPointerNode("panel", &log, scene)
+	a := newFocusableNode("a", &log, panel, -50.0, 0.0)
+	b := newFocusableNode("b", &log, panel, 50.0, 0.0)
+	c := newFocusableNode("c", &log, panel, -50.0, -50.0)
+	c.SetBoundBySize(10.0, 10.0)
+	d := newFocusableNode("d", &log, panel, 50.0, -50.0)
+	d.disabled = true
+
+	legacy := newPointerNode("legacy", &log, scene)
+	world.NodeManager().RegisterEventTarget(legacy)
+
+	if len(focus.Focusables()) != 3 {
+		t.Fatalf("Expected 3 focusables, got %v", focus.Focusables())
+	}
+
+	expect := func(expected ...string) {
+		t.Helper()
+		if fmt.Sprint(log) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, log)
+		}
+		log = log[:0]
+	}
+
+	send := func(event api.IEvent, expected ...string) {
+		t.Helper()
+		log = log[:0]
+		world.RouteEvents(event)
+		expect(expected...)
+	}
+
+	key := func(code, mods int) api.IEvent {
+		event := io.NewEvent()
+		event.SetType(api.IOTypeKeyboard)
+		event.SetState(api.KeyPress)
+		event.SetKeyCode(uint32(code))
+		event.SetKeyMotif(uint32(mods))
+		return event
+	}
+
+	focus.Navigate(api.FocusNext)
+	expect("a focus")
+	if focus.Focused() != a {
+		t.Errorf("Expected a to be focused, got %v", focus.Focused())
+	}
+
+	// The focused node and its parents see the key first, then the
+	// registered targets and only then does the focus move.
+	send(key(api.KeyRight, 0),
+		"a target key", "panel bubble key", "legacy none key", "a blur", "b focus")
+
+	send(key(api.KeyDown, 0),
+		"b target key", "panel bubble key", "legacy none key", "b blur", "c focus")
+
+	// Tab wraps and skips the disabled node.
+	send(key(api.KeyTab, 0),
+		"c target key", "panel bubble key", "legacy none key", "c blur", "a focus")
+	send(key(api.KeyTab, api.ModShift),
+		"a target key", "panel bubble key", "legacy none key", "a blur", "c focus")
+
+	pad := io.NewEvent()
+	pad.SetType(api.IOTypeGamepadButtonDown)
+	pad.SetButton(api.GamepadButtonDpadUp)
+	send(pad, "c target pad", "panel bubble pad", "legacy none pad", "c blur", "a focus")
+
+	// Nothing is left of a so the focus stays.
+	send(key(api.KeyLeft, 0),
+		"a target key", "panel bubble key", "legacy none key")
+
+	// A focused node that handles the key stops navigation.
+	b.takesKeys = true
+	focus.SetFocus(b)
+	expect("a blur", "b focus")
+	send(key(api.KeyLeft, 0), "b target key")
+
+	// Pressing a focusable node focuses it.
+	down := io.NewEvent()
+	down.SetType(api.IOTypeMouseButtonDown)
+	down.SetMousePosition(devicePoint(world, c, 0.0, 0.0))
+	send(down, "panel target enter", "c target enter",
+		"b blur", "c focus", "c target down", "panel bubble down", "legacy none down")
+
+	if focus.Focused() != c {
+		t.Errorf("Expected c to be focused, got %v", focus.Focused())
+	}
+
+	focus.ClearFocus()
+	expect("c blur")
+}
+
+// keyTarget is a registered target that may handle the keys.
+type keyTarget struct {
+	pointerNode
+	takesKeys bool
+}
+
+func (k *keyTarget) Handle(event api.IEvent) bool {
+	handled := k.pointerNode.Handle(event)
+	return handled || k.takesKeys && event.GetType() == api.IOTypeKeyboard
+}
+
+func testFocusWithKeyTarget(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := eng.World()
+	focus := world.NodeManager().Focus()
+	log := []string{}
+
+	a := newFocusableNode("a", &log, scene, -50.0, 0.0)
+	newFocusableNode("b", &log, scene, 50.0, 0.0)
+
+	// A game layer steering with the arrow keys.
+	game := new(keyTarget)
+	game.Initialize("game")
+	game.SetParent(scene)
+	scene.AddChild(game)
+	game.log = &log
+	game.takesKeys = true
+	world.NodeManager().RegisterEventTarget(game)
+
+	send := func(event api.IEvent, expected ...string) {
+		t.Helper()
+		log = log[:0]
+		world.RouteEvents(event)
+		if fmt.Sprint(log) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, log)
+		}
+	}
+
+	key := func(code int) api.IEvent {
+		event := io.NewEvent()
+		event.SetType(api.IOTypeKeyboard)
+		event.SetState(api.KeyPress)
+		event.SetKeyCode(uint32(code))
+		return event
+	}
+
+	pad := io.NewEvent()
+	pad.SetType(api.IOTypeGamepadButtonDown)
+	pad.SetButton(api.GamepadButtonDpadRight)
+
+	// Nothing is focused so the arrows and d-pad reach the game.
+	send(key(api.KeyRight), "game none key")
+	send(pad, "game none pad")
+	if focus.Focused() != nil {
+		t.Errorf("Expected nothing to be focused, got %v", focus.Focused())
+	}
+
+	// The game sees keys before navigation even once something has focus.
+	focus.SetFocus(a)
+	send(key(api.KeyRight), "a target key", "game none key")
+	if focus.Focused() != a {
+		t.Errorf("Expected a to stay focused, got %v", focus.Focused())
+	}
+
+	// Keys the game passes on still navigate.
+	game.takesKeys = false
+	send(key(api.KeyRight), "a target key", "game none key", "a blur", "b focus")
+}
+
+func names(list []api.INode) string {
+	s := ""
+	for _, node := range list {
+		s += node.Name()
+	}
+	return s
+}
+
+func testDrawOrder(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := eng.World()
+	man := world.NodeManager()
+
+	layer, _ := extras.NewGroupNode("Layer", world, scene)
+	nodesByName := map[string]api.INode{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		node, _ := extras.NewGroupNode(name, world, layer)
+		// All at the same place so the topmost is hit.
+		node.SetBoundBySize(10.0, 10.0)
+		nodesByName[name] = node
+	}
+
+	x, y := devicePoint(world, layer, 0.0, 0.0)
+
+	expect := func(order, top string) {
+		t.Helper()
+		if names(layer.DrawOrder()) != order {
+			t.Errorf("Expected draw order %s, got %s", order, names(layer.DrawOrder()))
+		}
+		if names(layer.Children()) != "abcd" {
+			t.Errorf("Expected the children to be unchanged, got %s", names(layer.Children()))
+		}
+		hit := man.HitTest(x, y)
+		if hit == nil || hit.Name() != top {
+			t.Errorf("Expected %s to be hit, got %v", top, hit)
+		}
+	}
+
+	expect("abcd", "d")
+
+	nodesByName["a"].SetZIndex(1)
+	expect("bcda", "a")
+
+	// Ties keep the order they were added in.
+	nodesByName["c"].SetZIndex(1)
+	expect("bdac", "c")
+
+	nodesByName["d"].SetZIndex(-1)
+	expect("dbac", "c")
+
+	man.SetSortingLayers("Background", nodes.DefaultSortingLayer, "Foreground")
+	nodesByName["b"].SetSortingLayer("Foreground")
+	nodesByName["c"].SetSortingLayer("Background")
+	expect("cdab", "b")
+
+	// Reordering the layers re-sorts every group.
+	man.SetSortingLayers("Foreground", nodes.DefaultSortingLayer, "Background")
+	expect("bdac", "c")
+
+	// Another engine's layers are its own.
+	other, _ := buildCountingGame(t)
+	other.World().NodeManager().SetSortingLayers("Background", nodes.DefaultSortingLayer, "Foreground")
+	expect("bdac", "c")
+	other.End()
+
+	man.SetSortingLayers()
+	nodesByName["b"].SetSortingLayer("")
+	nodesByName["c"].SetSortingLayer("")
+	nodesByName["a"].SetZIndex(0)
+	nodesByName["c"].SetZIndex(0)
+	nodesByName["d"].SetZIndex(0)
+	expect("abcd", "d")
+}
+
+// lifecycleNode registers for timing when it enters but, like a
+// careless node, never unregisters.
+type lifecycleNode struct {
+	nodes.Node
+	log     *[]string
+	updates int
+
+	// spawn names a child to add on entering
+	spawn string
+	// enteredBuilt is set if the node had a world when entered
+	enteredBuilt bool
+}
+
+func newLifecycleNode(name string, log *[]string, parent api.INode) *lifecycleNode {
+	o := new(lifecycleNode)
+	o.Initialize(name)
+	o.log = log
+	if parent != nil {
+		o.SetParent(parent)
+		parent.AddChild(o)
+	}
+	return o
+}
+
+func (l *lifecycleNode) EnterNode(man api.INodeManager) {
+	*l.log = append(*l.log, "enter "+l.Name())
+	man.RegisterTarget(l)
+
+	l.enteredBuilt = l.World() != nil
+	if l.spawn != "" {
+		newLifecycleNode(l.spawn, l.log, l)
+	}
+}
+
+func (l *lifecycleNode) ExitNode(man api.INodeManager) {
+	*l.log = append(*l.log, "exit "+l.Name())
+}
+
+func (l *lifecycleNode) Update(msPerUpdate, secPerUpdate float64) {
+	l.updates++
+}
+
+func testChildManagement(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	step := func(updates int) {
+		_, err := eng.Step(updates, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		step(0)
+	}
+
+	world := eng.World()
+	man := world.NodeManager()
+	log := []string{}
+
+	expect := func(expected ...string) {
+		t.Helper()
+		if fmt.Sprint(log) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, log)
+		}
+		log = nil
+	}
+
+	// A subtree built off stage is entered when it's attached.
+	branch := newLifecycleNode("branch", &log, nil)
+	leaf := newLifecycleNode("leaf", &log, branch)
+	expect()
+	if man.IsLive(leaf) {
+		t.Error("Expected a detached subtree not to be live")
+	}
+
+	branch.SetParent(scene)
+	scene.AddChild(branch)
+	if !man.IsLive(leaf) {
+		t.Error("Expected the attached subtree to be live")
+	}
+	expect()
+	step(1)
+	expect("enter branch", "enter leaf")
+
+	// Adding to a live node enters at the next update.
+	first := newLifecycleNode("first", &log, nil)
+	first.SetParent(branch)
+	branch.InsertChildAt(first, 0)
+	middle := newLifecycleNode("middle", &log, nil)
+	middle.SetParent(branch)
+	branch.InsertChildAt(middle, 1)
+	last := newLifecycleNode("last", &log, nil)
+	last.SetParent(branch)
+	branch.InsertChildAt(last, 99)
+	step(1)
+	expect("enter first", "enter middle", "enter last")
+	if names(branch.Children()) != "firstmiddleleaflast" {
+		t.Errorf("Expected the children in insertion order, got %s", names(branch.Children()))
+	}
+
+	// Prepending keeps every child.
+	zero := newLifecycleNode("zero", &log, nil)
+	zero.SetParent(branch)
+	branch.PrependChild(zero)
+	step(1)
+	expect("enter zero")
+	if names(branch.Children()) != "zerofirstmiddleleaflast" {
+		t.Errorf("Expected zero to be prepended, got %s", names(branch.Children()))
+	}
+
+	if !branch.RemoveChild(middle) {
+		t.Error("Expected middle to be removed")
+	}
+	expect("exit middle")
+	if middle.Parent() != nil || branch.RemoveChild(middle) {
+		t.Error("Expected middle to be detached once")
+	}
+
+	// Reparenting within the stage exits and re-enters, keeping the
+	// node where it was on screen.
+	other, _ := extras.NewGroupNode("Other", world, scene)
+	other.SetPosition(50.0, -20.0)
+	other.SetRotation(0.5)
+	other.SetScale(2.0)
+	leaf.SetPosition(10.0, 5.0)
+
+	before := geometry.NewPoint()
+	nodes.MapNodeToWorld(leaf, before)
+	nodes.Reparent(leaf, other, true)
+	step(1)
+	expect("exit leaf", "enter leaf")
+
+	after := geometry.NewPoint()
+	nodes.MapNodeToWorld(leaf, after)
+	if math.Abs(float64(before.X()-after.X())) > 0.01 || math.Abs(float64(before.Y()-after.Y())) > 0.01 {
+		t.Errorf("Expected leaf to stay at %v, got %v", before, after)
+	}
+	if leaf.Parent() != other || names(branch.Children()) != "zerofirstlast" {
+		t.Errorf("Expected leaf to move to Other, left %s", names(branch.Children()))
+	}
+
+	// Constructors add before they build, the node is entered built.
+	built := new(lifecycleNode)
+	built.Initialize("built")
+	built.log = &log
+	built.SetParent(other)
+	other.AddChild(built)
+	built.Build(world)
+
+	// A child added by EnterNode is entered once.
+	built.spawn = "spawned"
+	step(1)
+	expect("enter built", "enter spawned")
+	if !built.enteredBuilt {
+		t.Error("Expected the node to be built before it entered")
+	}
+
+	// A node removed before the update never enters or exits.
+	brief := newLifecycleNode("brief", &log, other)
+	other.RemoveChild(brief)
+	step(1)
+	expect()
+
+	// Destroying unregisters the nodes even though they don't.
+	step(1)
+	if zero.updates == 0 {
+		t.Fatal("Expected zero to be updated while registered")
+	}
+	updates := zero.updates
+	nodes.DestroySubtree(branch)
+	expect("exit branch", "exit zero", "exit first", "exit last")
+	step(1)
+	if zero.updates != updates || len(branch.Children()) != 0 {
+		t.Error("Expected the destroyed subtree to be unregistered and taken apart")
+	}
+	if man.IsLive(zero) {
+		t.Error("Expected the destroyed subtree not to be live")
+	}
+}
+
+func testQueries(t *testing.T) {
+	eng, scene := buildCountingGame(t)
+	defer eng.End()
+	for i := 0; i < 6; i++ {
+		_, err := eng.Step(0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world := eng.World()
+	man := world.NodeManager()
+	log := []string{}
+
+	layer, _ := extras.NewGroupNode("Game Layer", world, scene)
+	ship := newFocusableNode("Ship", &log, layer, 0.0, 0.0)
+	enemyA, _ := extras.NewGroupNode("EnemyA", world, layer)
+	enemyB, _ := extras.NewGroupNode("EnemyB", world, enemyA)
+	enemyA.AddTag("enemy")
+	enemyB.AddTag("enemy")
+	enemyB.AddTag("enemy")
+	enemyB.AddTag("boss")
+
+	found := man.FindNode("Scenes/" + scene.Name() + "/Game Layer/Ship")
+	if found != ship {
+		t.Errorf("Expected Ship from the root, got %v", found)
+	}
+	if nodes.FindPath(enemyB, "../../Ship") != ship || nodes.FindPath(enemyB, "/Game Layer/Ship") != ship {
+		t.Error("Expected relative and absolute paths from EnemyB")
+	}
+	if nodes.FindPath(layer, "Ship/Missing") != nil || nodes.FindPath(layer, "../../../../..") != nil {
+		t.Error("Expected missing paths to give nil")
+	}
+	if nodes.FindPath(layer, nodes.PathOf(ship)) != ship {
+		t.Errorf("Expected %s to lead back to Ship", nodes.PathOf(ship))
+	}
+
+	if names(nodes.FindByTag(layer, "enemy")) != "EnemyAEnemyB" || len(enemyB.Tags()) != 2 {
+		t.Errorf("Expected both enemies tagged once, got %s", names(nodes.FindByTag(layer, "enemy")))
+	}
+	enemyA.RemoveTag("enemy")
+	if names(nodes.FindByTag(scene, "enemy")) != "EnemyB" || enemyA.HasTag("enemy") {
+		t.Error("Expected only EnemyB to stay tagged")
+	}
+
+	if names(nodes.FindByName(scene, "Enemy*")) != "EnemyAEnemyB" {
+		t.Errorf("Expected a glob to find both enemies, got %s", names(nodes.FindByName(scene, "Enemy*")))
+	}
+
+	focusables := nodes.FindByType(scene, reflect.TypeOf((*api.IFocusable)(nil)).Elem())
+	if names(focusables) != "Ship" {
+		t.Errorf("Expected Ship to be the only focusable, got %s", names(focusables))
+	}
+	typ := reflect.TypeOf(scene)
+	if nodes.FindFirst(man.FindNode("Scenes"), func(n api.INode) bool { return reflect.TypeOf(n) == typ }) != scene ||
+		len(nodes.FindByType(layer, typ)) != 0 {
+		t.Error("Expected to find the scene by its concrete type")
+	}
+
+	first := nodes.FindFirst(scene, func(n api.INode) bool {
+		return n.Parent() == enemyA || n.Parent() == layer
+	})
+	if first != ship {
+		t.Errorf("Expected the depth-first search to reach Ship first, got %v", first)
+	}
+}
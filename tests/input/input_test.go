@@ -0,0 +1,548 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/input"
+	"github.com/wdevore/Ranger-Go-IGE/engine/io"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+)
+
+// go test -v -count=1 input_test.go
+
+const (
+	keyA     = 65
+	keyD     = 68
+	keyZ     = 90
+	keyShift = 340
+)
+
+func TestRunner(t *testing.T) {
+	testActions(t)
+	testModifiers(t)
+	testAxis(t)
+	testBindingsJSON(t)
+	testWorldInput(t)
+	testGamepads(t)
+	testGamepadDeadZone(t)
+	testClickCounts(t)
+	testRecordedFields(t)
+	testEventQueue(t)
+	testInputState(t)
+}
+
+func key(code int, state int) api.IEvent {
+	event := io.NewEvent()
+	event.SetType(api.IOTypeKeyboard)
+	event.SetKeyCode(uint32(code))
+	event.SetState(uint32(state))
+	return event
+}
+
+func mouse(button int, down bool) api.IEvent {
+	event := io.NewEvent()
+	if down {
+		event.SetType(api.IOTypeMouseButtonDown)
+	} else {
+		event.SetType(api.IOTypeMouseButtonUp)
+	}
+	event.SetButton(uint8(button))
+	return event
+}
+
+func expect(t *testing.T, tick string, m api.IInputMap, action string, pressed, held, released bool) {
+	if m.Pressed(action) != pressed || m.Held(action) != held || m.Released(action) != released {
+		t.Errorf("%s: '%s' expected pressed/held/released %v/%v/%v, got %v/%v/%v", tick, action,
+			pressed, held, released, m.Pressed(action), m.Held(action), m.Released(action))
+	}
+}
+
+func testActions(t *testing.T) {
+	m := input.NewInputMap()
+	m.BindKey("thrust", keyZ, 0)
+	m.BindMouseButton("fire", api.MouseButtonLeft, 0)
+
+	m.Handle(key(keyZ, api.KeyPress))
+	m.Update()
+	expect(t, "tick 1", m, "thrust", true, true, false)
+
+	m.Handle(key(keyZ, api.KeyRepeat))
+	m.Update()
+	expect(t, "tick 2", m, "thrust", false, true, false)
+
+	m.Handle(key(keyZ, api.KeyRelease))
+	m.Update()
+	expect(t, "tick 3", m, "thrust", false, false, true)
+
+	m.Update()
+	expect(t, "tick 4", m, "thrust", false, false, false)
+
+	// A click between two ticks isn't lost.
+	m.Handle(mouse(api.MouseButtonLeft, true))
+	m.Handle(mouse(api.MouseButtonLeft, false))
+	m.Update()
+	expect(t, "tap", m, "fire", true, false, true)
+
+	// Other buttons aren't bound.
+	m.Handle(mouse(api.MouseButtonRight, true))
+	m.Update()
+	expect(t, "right button", m, "fire", false, false, false)
+
+	// Rebinding
+	m.Unbind("thrust")
+	m.BindKey("thrust", keyA, 0)
+	m.Handle(key(keyZ, api.KeyPress))
+	m.Update()
+	expect(t, "old binding", m, "thrust", false, false, false)
+	m.Handle(key(keyA, api.KeyPress))
+	m.Update()
+	expect(t, "new binding", m, "thrust", true, true, false)
+}
+
+func testModifiers(t *testing.T) {
+	m := input.NewInputMap()
+	m.BindKey("fire", keyZ, api.ModShift)
+	m.BindModifier("boost", api.ModShift)
+
+	m.Handle(key(keyZ, api.KeyPress))
+	m.Update()
+	expect(t, "without shift", m, "fire", false, false, false)
+
+	m.Handle(key(keyShift, api.KeyPress))
+	m.Update()
+	expect(t, "with shift", m, "fire", true, true, false)
+	expect(t, "with shift", m, "boost", true, true, false)
+
+	m.Handle(key(keyShift, api.KeyRelease))
+	m.Update()
+	expect(t, "shift released", m, "fire", false, false, true)
+	expect(t, "shift released", m, "boost", false, false, true)
+}
+
+func testAxis(t *testing.T) {
+	m := input.NewInputMap()
+	m.BindKey("left", keyA, 0)
+	m.BindKey("right", keyD, 0)
+	m.BindAxis("turn", "left", "right")
+
+	m.Handle(key(keyA, api.KeyPress))
+	m.Update()
+	if m.Axis("turn") != -1.0 {
+		t.Errorf("Expected turn -1, got %f", m.Axis("turn"))
+	}
+
+	m.Handle(key(keyD, api.KeyPress))
+	m.Update()
+	if m.Axis("turn") != 0.0 {
+		t.Errorf("Expected turn 0 with both held, got %f", m.Axis("turn"))
+	}
+
+	m.Handle(key(keyA, api.KeyRelease))
+	m.Update()
+	if m.Axis("turn") != 1.0 {
+		t.Errorf("Expected turn 1, got %f", m.Axis("turn"))
+	}
+}
+
+func testBindingsJSON(t *testing.T) {
+	bindings := `{
+		"Actions": {
+			"thrust": [{"Key": "Z"}, {"Key": "Up"}],
+			"fire": [{"Mouse": "Left"}, {"Key": "Space", "Mods": ["Shift"]}],
+			"boost": [{"Modifier": "Control"}],
+			"pause": [{"Key": "P"}]
+		},
+		"Axes": {
+			"turn": {"Negative": "thrust", "Positive": "pause"}
+		}
+	}`
+
+	m := input.NewInputMap()
+	err := m.Load(strings.NewReader(bindings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := m.Actions()
+	if strings.Join(actions, ",") != "boost,fire,pause,thrust" {
+		t.Errorf("Unexpected actions: %v", actions)
+	}
+
+	m.Handle(key(265, api.KeyPress)) // Up
+	m.Update()
+	if !m.Pressed("thrust") || m.Axis("turn") != -1.0 {
+		t.Error("Expected the loaded bindings to be active")
+	}
+
+	// Save and load back
+	var saved bytes.Buffer
+	err = m.Save(&saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := input.NewInputMap()
+	err = reloaded.Load(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resaved bytes.Buffer
+	err = reloaded.Save(&resaved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.String() != resaved.String() {
+		t.Errorf("Expected saving to round trip:\n%s\n%s", saved.String(), resaved.String())
+	}
+
+	// A bad file leaves the bindings as they were.
+	for _, bad := range []string{
+		`{"Actions": {"fire": [{"Key": "NoSuchKey"}]}}`,
+		`{"Actions": {"fire": [{"Key": "Z", "Mouse": "Left"}]}}`,
+		`{"Actions": {"fire": [{"Button": "Left"}]}}`,
+	} {
+		err = m.Load(strings.NewReader(bad))
+		if err == nil {
+			t.Errorf("Expected an error loading: %s", bad)
+		}
+	}
+	if len(m.Actions()) != 4 {
+		t.Errorf("Expected the bindings to be kept, got %v", m.Actions())
+	}
+}
+
+func testWorldInput(t *testing.T) {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.End()
+
+	world := eng.World()
+	world.Input().BindKey("thrust", keyZ, 0)
+
+	world.RouteEvents(key(keyZ, api.KeyPress))
+	world.Input().Update()
+
+	if !world.Input().Pressed("thrust") {
+		t.Error("Expected routed events to reach the World's input map")
+	}
+}
+
+// eventLog records routed gamepad events as strings.
+type eventLog []string
+
+func (l *eventLog) route(event api.IEvent) {
+	s := ""
+	switch event.GetType() {
+	case api.IOTypeGamepadConnected:
+		s = "connected"
+	case api.IOTypeGamepadDisconnected:
+		s = "disconnected"
+	case api.IOTypeGamepadButtonDown:
+		s = fmt.Sprintf("down %d", event.GetButton())
+	case api.IOTypeGamepadButtonUp:
+		s = fmt.Sprintf("up %d", event.GetButton())
+	case api.IOTypeGamepadAxis:
+		axis, value := event.GetAxis()
+		s = fmt.Sprintf("axis %d %.2f", axis, value)
+	}
+	*l = append(*l, fmt.Sprintf("%d %s", event.GetWhich(), s))
+}
+
+func (l *eventLog) expect(t *testing.T, step string, events ...string) {
+	if strings.Join(*l, ",") != strings.Join(events, ",") {
+		t.Errorf("%s: expected events %v, got %v", step, events, *l)
+	}
+	*l = nil
+}
+
+func testGamepads(t *testing.T) {
+	mock := input.NewMockGamepadSource()
+	pads := input.NewGamepads(0.0)
+	pads.SetSource(mock)
+
+	log := &eventLog{}
+
+	pads.Poll(log.route)
+	log.expect(t, "no gamepads")
+
+	mock.Connect(1, "Mock pad")
+	pads.Poll(log.route)
+	log.expect(t, "connect", "1 connected")
+	if pads.Name(1) != "Mock pad" {
+		t.Errorf("Unexpected name: %s", pads.Name(1))
+	}
+
+	mock.SetButton(1, api.GamepadButtonA, true)
+	mock.SetAxis(1, api.GamepadAxisLeftX, 0.5)
+	mock.SetAxis(1, api.GamepadAxisRightTrigger, 1.0)
+	pads.Poll(log.route)
+	log.expect(t, "input", "1 down 0", "1 axis 0 0.50", "1 axis 5 1.00")
+
+	// Nothing changed so nothing is routed.
+	pads.Poll(log.route)
+	log.expect(t, "unchanged")
+
+	if !pads.Button(1, api.GamepadButtonA) || pads.Axis(1, api.GamepadAxisLeftX) != 0.5 {
+		t.Error("Expected the polled state to be kept")
+	}
+
+	mock.Disconnect(1)
+	pads.Poll(log.route)
+	log.expect(t, "disconnect", "1 up 0", "1 disconnected")
+	if len(pads.Connected()) != 0 {
+		t.Errorf("Expected no gamepads, got %v", pads.Connected())
+	}
+}
+
+func testGamepadDeadZone(t *testing.T) {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.End()
+
+	world := eng.World()
+	dz := world.Properties().Engine.GamepadDeadZone
+
+	mock := input.NewMockGamepadSource()
+	mock.Connect(0, "Mock pad")
+	pads := world.Gamepads()
+	pads.SetSource(mock)
+
+	// Resting within the dead-zone
+	mock.SetAxis(0, api.GamepadAxisLeftX, dz*0.5)
+	mock.SetAxis(0, api.GamepadAxisLeftY, -dz*0.5)
+	mock.SetAxis(0, api.GamepadAxisLeftTrigger, -1.0+dz)
+	pads.Poll(world.RouteEvents)
+	if pads.Axis(0, api.GamepadAxisLeftX) != 0.0 || pads.Axis(0, api.GamepadAxisLeftY) != 0.0 {
+		t.Error("Expected the stick to be within the dead-zone")
+	}
+	if pads.Axis(0, api.GamepadAxisLeftTrigger) != 0.0 {
+		t.Error("Expected the trigger to be within the dead-zone")
+	}
+
+	// The full range is still reachable, including diagonals.
+	mock.SetAxis(0, api.GamepadAxisLeftX, 1.0)
+	mock.SetAxis(0, api.GamepadAxisLeftY, 0.0)
+	pads.Poll(world.RouteEvents)
+	if pads.Axis(0, api.GamepadAxisLeftX) != 1.0 {
+		t.Errorf("Expected full deflection, got %f", pads.Axis(0, api.GamepadAxisLeftX))
+	}
+
+	mock.SetAxis(0, api.GamepadAxisLeftX, 0.5)
+	mock.SetAxis(0, api.GamepadAxisLeftY, 0.5)
+	pads.Poll(world.RouteEvents)
+	x, y := pads.Axis(0, api.GamepadAxisLeftX), pads.Axis(0, api.GamepadAxisLeftY)
+	if x != y || x <= 0.0 || math.Hypot(float64(x), float64(y)) >= math.Hypot(0.5, 0.5) {
+		t.Errorf("Expected the diagonal to keep its direction and be rescaled, got %f, %f", x, y)
+	}
+}
+
+func testClickCounts(t *testing.T) {
+	ms := int64(1000000)
+	clicks := io.NewClickCounter(io.DoubleClickInterval, io.DoubleClickDistance)
+
+	counts := []uint8{
+		clicks.Press(api.MouseButtonLeft, 100, 100, 0),
+		clicks.Press(api.MouseButtonLeft, 102, 99, 200*ms),
+		clicks.Press(api.MouseButtonLeft, 101, 101, 400*ms),
+		// Too slow
+		clicks.Press(api.MouseButtonLeft, 101, 101, 1000*ms),
+		// Moved too far
+		clicks.Press(api.MouseButtonLeft, 120, 101, 1100*ms),
+		// Another button
+		clicks.Press(api.MouseButtonRight, 120, 101, 1200*ms),
+		clicks.Press(api.MouseButtonRight, 120, 101, 1300*ms),
+	}
+
+	if fmt.Sprint(counts) != "[1 2 3 1 1 1 2]" {
+		t.Errorf("Unexpected click counts: %v", counts)
+	}
+
+	if clicks.Clicks(api.MouseButtonRight) != 2 || clicks.Clicks(api.MouseButtonLeft) != 1 {
+		t.Error("Expected a release to have its press's count")
+	}
+}
+
+func testRecordedFields(t *testing.T) {
+	recorder := input.NewInputRecorder()
+	recorder.Record()
+
+	text := io.NewEvent()
+	text.SetType(api.IOTypeText)
+	text.SetRune('é')
+	text.SetKeyMotif(api.ModShift)
+	recorder.Capture(text)
+
+	recorder.Advance(0, func(api.IEvent) {})
+
+	scroll := io.NewEvent()
+	scroll.SetType(api.IOTypeMouseWheel)
+	scroll.SetScroll(-0.5, 1.25)
+	scroll.SetMousePosition(-3, 40)
+	recorder.Capture(scroll)
+
+	recorder.Advance(1, func(api.IEvent) {})
+	recorder.Stop()
+
+	var recording bytes.Buffer
+	err := recorder.Save(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	played := []string{}
+	route := func(event api.IEvent) {
+		x, y := event.GetScroll()
+		mx, my := event.GetMousePosition()
+		played = append(played, fmt.Sprintf("%d %q %d %.2f %.2f %d %d",
+			event.GetType(), event.GetRune(), event.GetKeyMotif(), x, y, mx, my))
+	}
+
+	player := input.NewInputRecorder()
+	err = player.Play(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tick := uint64(0); tick < 3; tick++ {
+		player.Advance(tick, route)
+	}
+
+	expected := "[1028 'é' 1 0.00 0.00 0 0 1027 '\\x00' 0 -0.50 1.25 -3 40]"
+	if fmt.Sprint(played) != expected {
+		t.Errorf("Expected %s, got %v", expected, played)
+	}
+}
+
+func motion(x, y int32) api.IEvent {
+	event := io.NewEvent()
+	event.SetType(api.IOTypeMouseMotion)
+	event.SetMousePosition(x, y)
+	event.SetMouseRelMovement(1, 2)
+	return event
+}
+
+func testEventQueue(t *testing.T) {
+	queue := io.NewEventQueue()
+
+	// The queue keeps copies.
+	event := key(keyZ, api.KeyPress)
+	event.SetTimestamp(100)
+	queue.Post(event)
+	event.SetKeyCode(keyA)
+
+	queue.Post(motion(1, 1))
+	queue.Post(motion(2, 2))
+	queue.Post(motion(3, 3))
+	queue.Post(mouse(api.MouseButtonLeft, true))
+	queue.Post(motion(4, 4))
+
+	if queue.Len() != 4 {
+		t.Fatalf("Expected the motion to be coalesced into 4 events, got %d", queue.Len())
+	}
+
+	if queue.Peek(0).GetKeyCode() != keyZ || queue.Peek(0).GetTimestamp() != 100 {
+		t.Error("Expected the queued event to be a copy")
+	}
+
+	// Coalesced motion has the latest position and the total movement.
+	x, y := queue.Peek(1).GetMousePosition()
+	rx, ry := queue.Peek(1).GetMouseRelMovement()
+	if x != 3 || y != 3 || rx != 3 || ry != 6 {
+		t.Errorf("Unexpected coalesced motion: %d,%d moved %d,%d", x, y, rx, ry)
+	}
+
+	// A handler may consume events that haven't been routed yet.
+	routed := []uint32{}
+	queue.Dispatch(func(event api.IEvent) {
+		routed = append(routed, event.GetType())
+		if event.GetType() == api.IOTypeMouseMotion && queue.Len() > 0 &&
+			queue.Peek(0).GetType() == api.IOTypeMouseButtonDown {
+			queue.Consume(0)
+		}
+	})
+
+	expected := fmt.Sprint([]uint32{api.IOTypeKeyboard, api.IOTypeMouseMotion, api.IOTypeMouseMotion})
+	if fmt.Sprint(routed) != expected {
+		t.Errorf("Expected %s routed, got %v", expected, routed)
+	}
+	if queue.Len() != 0 {
+		t.Error("Expected the queue to be empty after dispatching")
+	}
+
+	queue.SetCoalesceMotion(false)
+	queue.Post(motion(1, 1))
+	queue.Post(motion(2, 2))
+	if queue.Len() != 2 {
+		t.Errorf("Expected motion to be kept, got %d events", queue.Len())
+	}
+}
+
+func testInputState(t *testing.T) {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.End()
+
+	world := eng.World()
+	state := world.InputState()
+
+	world.RouteEvents(key(keyA, api.KeyPress))
+	world.RouteEvents(motion(100, 200))
+	state.Update()
+
+	if !state.IsDown(keyA) || !state.WentDown(keyA) || state.WentUp(keyA) {
+		t.Error("Expected A to have gone down")
+	}
+
+	expected := geometry.NewPoint()
+	nodes.MapDeviceToView(world, 100, 200, expected)
+	if x, y := state.Cursor(); x != 100 || y != 200 {
+		t.Errorf("Unexpected cursor %d, %d", x, y)
+	}
+	if state.CursorView().X() != expected.X() || state.CursorView().Y() != expected.Y() {
+		t.Errorf("Expected the cursor mapped to view-space %v, got %v", expected, state.CursorView())
+	}
+
+	world.RouteEvents(motion(110, 220))
+	state.Update()
+
+	if !state.IsDown(keyA) || state.WentDown(keyA) {
+		t.Error("Expected A to still be held")
+	}
+	if x, y := state.PreviousCursor(); x != 100 || y != 200 {
+		t.Errorf("Unexpected previous cursor %d, %d", x, y)
+	}
+	if state.PreviousCursorView().X() != expected.X() || state.PreviousCursorView().Y() != expected.Y() {
+		t.Error("Expected the previous cursor in view-space")
+	}
+
+	world.RouteEvents(key(keyA, api.KeyRelease))
+	// A click between two ticks is down for one tick.
+	world.RouteEvents(mouse(api.MouseButtonLeft, true))
+	world.RouteEvents(mouse(api.MouseButtonLeft, false))
+	state.Update()
+
+	if state.IsDown(keyA) || !state.WentUp(keyA) {
+		t.Error("Expected A to have gone up")
+	}
+	if !state.IsButtonDown(api.MouseButtonLeft) || !state.ButtonWentDown(api.MouseButtonLeft) {
+		t.Error("Expected the click to be down for a tick")
+	}
+
+	state.Update()
+	if state.IsButtonDown(api.MouseButtonLeft) || !state.ButtonWentUp(api.MouseButtonLeft) {
+		t.Error("Expected the click to have gone up")
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/atlas"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/color"
+	"github.com/wdevore/Ranger-Go-IGE/extras"
+	"github.com/wdevore/Ranger-Go-IGE/extras/serialization"
+	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
+)
+
+// go test -v -count=1 serialization_test.go
+
+func TestRunner(t *testing.T) {
+	testRoundTrip(t)
+	testCustomType(t)
+	testErrors(t)
+}
+
+func newWorld(t *testing.T) api.IWorld {
+	eng, err := engine.ConstructHeadless("../..", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(eng.End)
+
+	world := eng.World()
+	if world.GetAtlas(api.MonoAtlasName) == nil {
+		world.AddAtlas(api.MonoAtlasName, atlas.NewStaticMonoAtlas(world))
+	}
+
+	return world
+}
+
+// save returns node's subtree as JSON.
+func save(t *testing.T, s api.ISceneSerializer, node api.INode) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	err := s.Save(node, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func testRoundTrip(t *testing.T) {
+	world := newWorld(t)
+	s := serialization.NewSceneSerializer()
+
+	root, _ := extras.NewGroupNode("Root", world, nil)
+	layer, _ := extras.NewGroupNode("Game Layer", world, root)
+	layer.SetPosition(10.0, 20.0)
+
+	sq, err := shapes.NewMonoSquareNode("Ship", api.FILLOUTLINED, true, world, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sq.SetPosition(100.0, -50.0)
+	sq.SetRotation(0.5)
+	sq.SetScaleComps(25.0, 30.0)
+	sq.SetZIndex(2)
+	sq.SetSortingLayer("Foreground")
+	sq.AddTag("player")
+	sq.(*shapes.MonoSquareNode).SetFilledColor(color.NewPaletteFromFloats(1.0, 0.5, 0.0, 1.0))
+
+	arc, err := shapes.NewMonoArcNode("Radar", api.OUTLINED, 8, 0.0, math.Pi/2.0, world, sq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arc.SetVisible(false)
+	arc.SetOpacity(0.25)
+	arc.SetTint(color.NewPaletteFromFloats(0.0, 1.0, 0.0, 1.0))
+
+	line, _ := shapes.NewMonoHLineNode("Horizon", world, layer)
+	line.(*shapes.MonoHLineNode).SetColor(color.NewPaletteFromFloats(0.0, 0.0, 1.0, 0.5))
+	shapes.NewMonoCircleNode("Sun", api.FILLED, 16, world, layer)
+
+	saved := save(t, s, layer)
+
+	loaded, err := s.Load(strings.NewReader(saved), world, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again := save(t, s, loaded); again != saved {
+		t.Errorf("Expected the loaded subtree to save the same, got\n%s\nexpected\n%s", again, saved)
+	}
+
+	if loaded.Parent() != root || len(root.Children()) != 2 {
+		t.Error("Expected the loaded layer to be added to Root")
+	}
+
+	ship, ok := nodes.FindPath(loaded, "Ship").(*shapes.MonoSquareNode)
+	if !ok {
+		t.Fatalf("Expected a MonoSquareNode Ship, got %v", nodes.FindPath(loaded, "Ship"))
+	}
+	sx, sy := ship.ScaleComps()
+	if ship.Position().X() != 100.0 || ship.Rotation() != 0.5 || sx != 25.0 || sy != 30.0 {
+		t.Errorf("Expected Ship's transform, got %v %v %v %v", ship.Position(), ship.Rotation(), sx, sy)
+	}
+	if ship.DrawStyle() != api.FILLOUTLINED || !ship.Centered() || ship.FilledColor().G() != 0.5 {
+		t.Errorf("Expected Ship's shape, got %d %v %v", ship.DrawStyle(), ship.Centered(), ship.FilledColor())
+	}
+	if ship.ZIndex() != 2 || ship.SortingLayer() != "Foreground" || !ship.HasTag("player") {
+		t.Error("Expected Ship's ordering and tags")
+	}
+
+	radar := nodes.FindPath(loaded, "Ship/Radar").(*shapes.MonoArcNode)
+	start, end := radar.Angles()
+	if radar.IsVisible() || radar.Segments() != 8 || start != 0.0 || end != math.Pi/2.0 {
+		t.Error("Expected Radar to be a hidden quarter arc of 8 segments")
+	}
+	if radar.Opacity() != 0.25 || radar.Tint().R() != 0.0 || ship.Opacity() != 1.0 {
+		t.Error("Expected Radar to be a faded green")
+	}
+
+	if nodes.FindPath(loaded, "Horizon").(*shapes.MonoHLineNode).Color().A() != 0.5 {
+		t.Error("Expected Horizon's color")
+	}
+}
+
+// engineNode builds its own Flame child, like many game nodes do.
+type engineNode struct {
+	nodes.Node
+	thrust float32
+}
+
+type engineJSON struct {
+	Thrust float32
+}
+
+func newEngineNode(name string, thrust float32, world api.IWorld, parent api.INode) (api.INode, error) {
+	o := new(engineNode)
+	o.Initialize(name)
+	o.SetParent(parent)
+	parent.AddChild(o)
+	o.thrust = thrust
+
+	_, err := shapes.NewMonoTriangleNode("Flame", api.FILLED, world, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func testCustomType(t *testing.T) {
+	world := newWorld(t)
+	s := serialization.NewSceneSerializer()
+	s.Register("Engine", &engineNode{}, serialization.NewCodec(
+		func(node api.INode) (interface{}, error) {
+			return &engineJSON{Thrust: node.(*engineNode).thrust}, nil
+		},
+		func(name string, properties json.RawMessage, world api.IWorld, parent api.INode) (api.INode, error) {
+			ej := &engineJSON{}
+			err := json.Unmarshal(properties, ej)
+			if err != nil {
+				return nil, err
+			}
+			return newEngineNode(name, ej.Thrust, world, parent)
+		}))
+
+	root, _ := extras.NewGroupNode("Root", world, nil)
+	eng, err := newEngineNode("Engine", 3.5, world, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flame := eng.GetChildByName("Flame")
+	flame.SetScale(5.0)
+	shapes.NewMonoPlusNode("Marker", world, eng)
+	shapes.NewMonoPlusNode("Marker", world, eng)
+
+	saved := save(t, s, eng)
+	if !strings.Contains(saved, `"Thrust": 3.5`) {
+		t.Errorf("Expected the engine's own properties, got\n%s", saved)
+	}
+
+	loaded, err := s.Load(strings.NewReader(saved), world, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Flame the constructor built is updated rather than added
+	// again, while both Markers are loaded.
+	children := loaded.Children()
+	if len(children) != 3 || children[0].Name() != "Flame" || children[0].Scale() != 5.0 {
+		t.Errorf("Expected Flame, updated, and two Markers, got %v", children)
+	}
+	if loaded.(*engineNode).thrust != 3.5 {
+		t.Errorf("Expected a thrust of 3.5, got %v", loaded.(*engineNode).thrust)
+	}
+}
+
+func testErrors(t *testing.T) {
+	world := newWorld(t)
+	s := serialization.NewSceneSerializer()
+
+	root, _ := extras.NewGroupNode("Root", world, nil)
+	newEngineNode("Engine", 1.0, world, root)
+
+	err := s.Save(root, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "'Engine'") {
+		t.Errorf("Expected an error naming the unregistered node, got %v", err)
+	}
+
+	_, err = s.Load(strings.NewReader(`{"Type": "Group", "Name": "A", "Children": [{"Type": "Ship", "Name": "B"}]}`), world, root)
+	if err == nil || !strings.Contains(err.Error(), "unknown type 'Ship'") {
+		t.Errorf("Expected an unknown type error, got %v", err)
+	}
+
+	_, err = s.Load(strings.NewReader(`{"Type": "MonoSquare", "Name": "A", "Properties": {"DrawStyle": "Dotted"}}`), world, root)
+	if err == nil || !strings.Contains(err.Error(), "Dotted") {
+		t.Errorf("Expected a draw style error, got %v", err)
+	}
+
+	_, err = s.Load(strings.NewReader(`{"Type": "Group", "Name": "A", "Colour": [1, 0, 0, 1]}`), world, root)
+	if err == nil || !strings.Contains(err.Error(), "Colour") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+
+	_, err = s.Load(strings.NewReader(`{"Type": "Group", "Name": "A", "Children": [{"Type": "MonoSquare", "Name": "B", "Properties": {"Sides": 4}}]}`), world, root)
+	if err == nil || !strings.Contains(err.Error(), "Sides") {
+		t.Errorf("Expected an unknown property error, got %v", err)
+	}
+
+	// Nothing is left of the failed loads.
+	if names := strings.Join(nodeNames(root.Children()), ","); names != "Engine" {
+		t.Errorf("Expected only Engine under Root, got %s", names)
+	}
+}
+
+func nodeNames(list []api.INode) []string {
+	names := []string{}
+	for _, node := range list {
+		names = append(names, node.Name())
+	}
+	return names
+}
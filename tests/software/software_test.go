@@ -0,0 +1,424 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wdevore/Ranger-Go-IGE/api"
+	"github.com/wdevore/Ranger-Go-IGE/engine"
+	"github.com/wdevore/Ranger-Go-IGE/engine/display"
+	"github.com/wdevore/Ranger-Go-IGE/engine/geometry"
+	"github.com/wdevore/Ranger-Go-IGE/engine/maths"
+	"github.com/wdevore/Ranger-Go-IGE/engine/nodes"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/atlas"
+	rcolor "github.com/wdevore/Ranger-Go-IGE/engine/rendering/color"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/fonts"
+	"github.com/wdevore/Ranger-Go-IGE/engine/rendering/raster"
+	"github.com/wdevore/Ranger-Go-IGE/extras"
+	"github.com/wdevore/Ranger-Go-IGE/extras/shapes"
+)
+
+// go test -v -count=1 software_test.go
+
+var (
+	red   = color.NRGBA{R: 255, G: 0, B: 0, A: 255}
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+func TestRunner(t *testing.T) {
+	testRasterizeScene(t)
+	testViewportPolicies(t)
+	testLetterboxResize(t)
+	testHotReload(t)
+	testOpacityAndTint(t)
+	testClippedLines(t)
+}
+
+type rasterScene struct {
+	nodes.Node
+	nodes.Scene
+}
+
+// newRasterScene has a 100x100 red square at the center and the
+// letter "A" to the upper left.
+func newRasterScene(name string, world api.IWorld) (*rasterScene, error) {
+	o := new(rasterScene)
+	o.Initialize(name)
+	o.SetParent(world.Scenes())
+	world.Scenes().AddChild(o)
+
+	err := o.Build(world)
+	if err != nil {
+		return nil, err
+	}
+
+	square, err := shapes.NewMonoSquareNode("Square", api.FILLED, true, world, o)
+	if err != nil {
+		return nil, err
+	}
+	square.SetScale(100.0)
+	square.(*shapes.MonoSquareNode).SetFilledColor(rcolor.NewPaletteInt64(rcolor.Red))
+
+	spriteSheet := fonts.NewFont9x9SpriteSheet("Font9x9", "font9x9_sprite_sheet_manifest.json")
+	spriteSheet.Load("../../examples/assets/", true)
+
+	textAtlas := atlas.NewSingleTextureAtlas("Font9x9", spriteSheet, world)
+	err = textAtlas.Burn()
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := shapes.NewBitmapFont9x9Node("Text", textAtlas, world, o)
+	if err != nil {
+		return nil, err
+	}
+	text.SetScale(90.0)
+	text.SetPosition(-300.0, 200.0)
+	text.(*shapes.BitmapFont9x9Node).SetText("A")
+
+	return o, nil
+}
+
+func countColor(img *image.NRGBA, rect image.Rectangle, c color.NRGBA) int {
+	count := 0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if img.NRGBAAt(x, y) == c {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// buildRasterGame constructs a software engine and steps it until the
+// raster scene is on stage.
+func buildRasterGame(t *testing.T, overrides string) api.IEngine {
+	eng, err := engine.ConstructSoftware("../..", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	world := eng.World()
+
+	monoAtlas := world.GetAtlas(api.MonoAtlasName)
+	if monoAtlas == nil {
+		monoAtlas = atlas.NewStaticMonoAtlas(world)
+		world.AddAtlas(api.MonoAtlasName, monoAtlas)
+	}
+
+	scene, err := newRasterScene("Raster", world)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.Push(scene)
+	world.Push(extras.NewBasicBootScene("Boot"))
+
+	err = monoAtlas.Burn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Let the Boot scene exit and the raster scene enter.
+	for i := 0; i < 6; i++ {
+		_, err = eng.Step(1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return eng
+}
+
+func testRasterizeScene(t *testing.T) {
+	eng := buildRasterGame(t, "")
+	defer eng.End()
+
+	world := eng.World()
+
+	img := world.Rasterizer().Image()
+	dvr := world.Properties().Window.DeviceRes
+	if img.Bounds().Dx() != dvr.Width || img.Bounds().Dy() != dvr.Height {
+		t.Fatalf("expected image to match device resolution, got %v", img.Bounds())
+	}
+
+	// The camera is centered so the square covers exactly 100x100
+	// pixels around the middle of the image.
+	cx, cy := dvr.Width/2, dvr.Height/2
+	squareRect := image.Rect(cx-50, cy-50, cx+50, cy+50)
+	if n := countColor(img, squareRect, red); n != 100*100 {
+		t.Errorf("expected 10000 red pixels in the square, got %d", n)
+	}
+	if n := countColor(img, img.Bounds(), red); n != 100*100 {
+		t.Errorf("expected 10000 red pixels in total, got %d", n)
+	}
+
+	wc := world.Properties().Window.ClearColor
+	corner := img.NRGBAAt(0, 0)
+	if corner.R != uint8(wc.R*255+0.5) || corner.G != uint8(wc.G*255+0.5) || corner.B != uint8(wc.B*255+0.5) {
+		t.Errorf("expected the clear color in the corner, got %v", corner)
+	}
+
+	// The text is drawn with a white mix color so only the glyph's
+	// pixels are white. Note: the image's y axis is down.
+	textRect := image.Rect(cx-300, cy-200-60, cx-300+140, cy-200+60)
+	if n := countColor(img, textRect, white); n == 0 {
+		t.Error("expected the glyph to be rasterized")
+	}
+}
+
+func testViewportPolicies(t *testing.T) {
+	tests := []struct {
+		policy     string
+		viewport   [4]int
+		viewWidth  float32
+		viewHeight float32
+	}{
+		{api.ScaleNone, [4]int{0, 0, 1000, 500}, 1000, 500},
+		{api.ScaleStretch, [4]int{0, 0, 1000, 500}, 400, 300},
+		{api.ScaleLetterbox, [4]int{166, 0, 667, 500}, 400, 300},
+		{api.ScalePixelPerfect, [4]int{300, 100, 400, 300}, 400, 300},
+		{api.ScaleExpand, [4]int{0, 0, 1000, 500}, 600, 300},
+	}
+
+	for _, test := range tests {
+		viewport := display.NewViewport()
+		viewport.Configure(test.policy, 400, 300)
+		viewport.Resize(1000, 500)
+
+		x, y, w, h := viewport.Dimensions()
+		if [4]int{x, y, w, h} != test.viewport {
+			t.Errorf("%s: expected viewport %v, got %v", test.policy, test.viewport, [4]int{x, y, w, h})
+		}
+
+		vw, vh := viewport.ViewSize()
+		if vw != test.viewWidth || vh != test.viewHeight {
+			t.Errorf("%s: expected view %vx%v, got %vx%v", test.policy, test.viewWidth, test.viewHeight, vw, vh)
+		}
+	}
+}
+
+func testLetterboxResize(t *testing.T) {
+	eng := buildRasterGame(t, "letterbox.json")
+	defer eng.End()
+
+	world := eng.World()
+	img := world.Rasterizer().Image()
+
+	// 400x300 virtual in a 800x400 device is scaled by 4/3 which
+	// leaves pillars of 133 pixels on the left and right.
+	x, _, w, _ := world.Viewport().Dimensions()
+	if x != 133 || w != 533 {
+		t.Fatalf("expected a pillarboxed viewport, got x: %d, width: %d", x, w)
+	}
+	if n := countColor(img, image.Rect(0, 0, x, 400), red); n != 0 {
+		t.Errorf("expected nothing drawn in the pillar, got %d red pixels", n)
+	}
+	if n := countColor(img, img.Bounds(), red); n < 133*133 || n > 134*134 {
+		t.Errorf("expected a 133x133 square, got %d red pixels", n)
+	}
+
+	// Scale up by 3 and the bars disappear.
+	world.Resize(1200, 900)
+	_, err := eng.Step(0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img = world.Rasterizer().Image()
+	if img.Bounds().Dx() != 1200 || img.Bounds().Dy() != 900 {
+		t.Fatalf("expected the image to be resized, got %v", img.Bounds())
+	}
+	if n := countColor(img, img.Bounds(), red); n != 300*300 {
+		t.Errorf("expected a 300x300 square, got %d red pixels", n)
+	}
+
+	// The device's center (+Y up) maps to the view's origin and a
+	// device pixel is 1/3 of a view unit.
+	viewPoint := geometry.NewPoint()
+	nodes.MapDeviceToView(world, 600+30, 450+60, viewPoint)
+	if viewPoint.X() != 10.0 || viewPoint.Y() != 20.0 {
+		t.Errorf("expected device to map to (10,20), got (%v,%v)", viewPoint.X(), viewPoint.Y())
+	}
+}
+
+func testHotReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hotreload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "config.json")
+	writeFile(t, config, `{"Engine": {"HotReload": true, "HotReloadPoll": 0}, "Window": {"ClearColor": {"R": 0, "G": 0, "B": 1, "A": 1}}}`)
+
+	eng := buildRasterGame(t, config)
+	defer eng.End()
+
+	world := eng.World()
+	blue := color.NRGBA{R: 0, G: 0, B: 255, A: 255}
+	if c := world.Rasterizer().Image().NRGBAAt(0, 0); c != blue {
+		t.Fatalf("expected a blue clear color, got %v", c)
+	}
+
+	// The config is reloaded and re-applied between frames.
+	writeFile(t, config, `{"Engine": {"HotReload": true, "HotReloadPoll": 0}, "Window": {"ClearColor": {"R": 0.0, "G": 1.0, "B": 0.0, "A": 1.0}}}`)
+	stepFrame(t, eng)
+
+	green := color.NRGBA{R: 0, G: 255, B: 0, A: 255}
+	if c := world.Rasterizer().Image().NRGBAAt(0, 0); c != green {
+		t.Errorf("expected the reloaded green clear color, got %v", c)
+	}
+
+	// A broken config is reported and the current values are kept.
+	reloads := eng.HotReloader().Reloads()
+	writeFile(t, config, `{"Engine": {"UPSRate": 0}}`)
+	stepFrame(t, eng)
+
+	if r := eng.HotReloader().Reloads(); r != reloads {
+		t.Errorf("expected a failed reload not to be counted, got %d reloads from %d", r, reloads)
+	}
+
+	if c := world.Rasterizer().Image().NRGBAAt(0, 0); c != green {
+		t.Errorf("expected the clear color to be kept, got %v", c)
+	}
+
+	// Sprite sheets are reloaded in place.
+	copyFile(t, "../../examples/assets/font9x9_sprite_sheet_manifest.json", filepath.Join(dir, "font9x9_sprite_sheet_manifest.json"))
+	copyFile(t, "../../examples/assets/font9x9_sprite_sheet.png", filepath.Join(dir, "font9x9_sprite_sheet.png"))
+
+	spriteSheet := fonts.NewFont9x9SpriteSheet("Reload", "font9x9_sprite_sheet_manifest.json")
+	spriteSheet.Load(dir, true)
+	textureAtlas := atlas.NewSingleTextureAtlas("Reload", spriteSheet, world)
+	err = textureAtlas.Burn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	f, err := os.Create(filepath.Join(dir, "font9x9_sprite_sheet.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = png.Encode(f, sheet)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stepFrame(t, eng)
+
+	if w := spriteSheet.SheetImage().Bounds().Dx(); w != 64 {
+		t.Errorf("expected the sprite sheet image to be reloaded, got width %d", w)
+	}
+	if r := eng.HotReloader().Reloads(); r != reloads+1 {
+		t.Errorf("expected the sprite sheet reload to be counted, got %d reloads from %d", r, reloads)
+	}
+}
+
+func stepFrame(t *testing.T, eng api.IEngine) {
+	_, err := eng.Step(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, file, data string) {
+	err := ioutil.WriteFile(file, []byte(data), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func copyFile(t *testing.T, from, to string) {
+	data, err := ioutil.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(to, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testOpacityAndTint(t *testing.T) {
+	eng := buildRasterGame(t, "")
+	defer eng.End()
+
+	world := eng.World()
+	scene := nodes.FindPath(world.Scenes(), "Raster")
+	square := nodes.FindPath(scene, "Square")
+
+	dvr := world.Properties().Window.DeviceRes
+	center := func() color.NRGBA {
+		return world.Rasterizer().Image().NRGBAAt(dvr.Width/2, dvr.Height/2)
+	}
+	near := func(channel uint8, expected float32) bool {
+		d := float32(channel) - expected*255.0
+		return d > -1.5 && d < 1.5
+	}
+
+	// A tint on the scene reaches the square.
+	scene.SetTint(rcolor.NewPaletteFromFloats(0.5, 1.0, 1.0, 1.0))
+	stepFrame(t, eng)
+	if c := center(); !near(c.R, 0.5) || c.G != 0 || c.B != 0 {
+		t.Errorf("expected a dark red square, got %v", c)
+	}
+
+	// Opacities multiply down to a quarter and blend with the clear
+	// color.
+	scene.SetTint(nil)
+	if r, g, b, a := scene.Tint().Components(); r != 1.0 || g != 1.0 || b != 1.0 || a != 1.0 {
+		t.Errorf("expected no tint to read as white, got %v %v %v %v", r, g, b, a)
+	}
+	scene.SetOpacity(0.5)
+	square.SetOpacity(0.5)
+	stepFrame(t, eng)
+
+	wc := world.Properties().Window.ClearColor
+	if c := center(); !near(c.R, 0.25+wc.R*0.75) || !near(c.G, wc.G*0.75) || !near(c.B, wc.B*0.75) {
+		t.Errorf("expected a quarter opaque square, got %v", c)
+	}
+
+	scene.SetOpacity(1.0)
+	square.SetOpacity(1.0)
+	stepFrame(t, eng)
+	if c := center(); c != red {
+		t.Errorf("expected the square to be red again, got %v", c)
+	}
+}
+
+func testClippedLines(t *testing.T) {
+	eng := buildRasterGame(t, "")
+	defer eng.End()
+
+	world := eng.World()
+	rasterizer := world.Rasterizer()
+	img := rasterizer.Image()
+	rasterizer.Clear(0.0, 0.0, 0.0, 1.0)
+
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	vertices := []float32{
+		// Far beyond both sides, only the visible part is stepped.
+		-1.0e9, 0.5, 0.0,
+		1.0e9, 0.5, 0.0,
+		// Not drawn at all
+		nan, 0.0, 0.0,
+		10.0, 10.0, 0.0,
+		0.0, 0.0, 0.0,
+		inf, -inf, 0.0,
+	}
+	indices := []uint32{0, 1, 2, 3, 4, 5}
+	rasterizer.DrawElements(raster.Lines, vertices, indices, len(indices), maths.NewMatrix4(), []float32{1.0, 0.0, 0.0, 1.0})
+
+	if n := countColor(img, img.Bounds(), red); n != img.Bounds().Dx() {
+		t.Errorf("expected one red row of %d pixels, got %d", img.Bounds().Dx(), n)
+	}
+}